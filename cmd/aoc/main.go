@@ -0,0 +1,21 @@
+package main
+
+import (
+	_ "github.com/alex-whitney/advent-of-code-2025/day1/solution"
+	_ "github.com/alex-whitney/advent-of-code-2025/day10/solution"
+	_ "github.com/alex-whitney/advent-of-code-2025/day11/solution"
+	_ "github.com/alex-whitney/advent-of-code-2025/day12/solution"
+	_ "github.com/alex-whitney/advent-of-code-2025/day2/solution"
+	_ "github.com/alex-whitney/advent-of-code-2025/day3/solution"
+	_ "github.com/alex-whitney/advent-of-code-2025/day4/solution"
+	_ "github.com/alex-whitney/advent-of-code-2025/day5/solution"
+	_ "github.com/alex-whitney/advent-of-code-2025/day6/solution"
+	_ "github.com/alex-whitney/advent-of-code-2025/day7/solution"
+	_ "github.com/alex-whitney/advent-of-code-2025/day8/solution"
+	_ "github.com/alex-whitney/advent-of-code-2025/day9/solution"
+	"github.com/alex-whitney/advent-of-code-2025/lib"
+)
+
+func main() {
+	lib.Main()
+}
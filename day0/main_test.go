@@ -3,26 +3,18 @@ package main
 import (
 	"testing"
 
-	"github.com/stretchr/testify/assert"
-	"github.com/stretchr/testify/require"
+	"github.com/alex-whitney/advent-of-code-2025/lib"
+	"github.com/alex-whitney/advent-of-code-2025/lib/aoctest"
 )
 
-func TestPart1(t *testing.T) {
-	d := &Today{}
-	err := d.Init("sample.txt")
-	require.NoError(t, err)
-
-	result, err := d.Part1()
-	require.NoError(t, err)
-	assert.Equal(t, "Hello", result)
+func newDay() lib.Day {
+	return &Today{}
 }
 
-func TestPart2(t *testing.T) {
-	d := &Today{}
-	err := d.Init("sample.txt")
-	require.NoError(t, err)
+func TestAnswers(t *testing.T) {
+	aoctest.Run(t, newDay, aoctest.AnswerCases(t, "."))
+}
 
-	result, err := d.Part2()
-	require.NoError(t, err)
-	assert.Equal(t, "World", result)
+func BenchmarkAnswers(b *testing.B) {
+	aoctest.Bench(b, newDay, aoctest.AnswerCases(b, "."))
 }
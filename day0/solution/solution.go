@@ -0,0 +1,24 @@
+package solution
+
+import (
+	"github.com/alex-whitney/advent-of-code-2025/lib"
+)
+
+func init() {
+	lib.Register(0, func() lib.Day { return &Today{} })
+}
+
+type Today struct {
+}
+
+func (d *Today) Init(input string) error {
+	return nil
+}
+
+func (d *Today) Part1() (string, error) {
+	return "Hello", nil
+}
+
+func (d *Today) Part2() (string, error) {
+	return "World", nil
+}
@@ -0,0 +1,160 @@
+package solution
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/alex-whitney/advent-of-code-2025/lib"
+	"github.com/thomaso-mirodin/intmath/intgr"
+)
+
+// the linear algebra approach the comments in Part2Context were hoping for
+//
+// each counter gives an equation: the presses of the buttons wired to it have to add up to its
+// joltage. Gauss-Jordan elimination solves for most of the buttons in terms of a few "free"
+// buttons, usually no more than 3, and then it's cheap to try every count for the free buttons
+// and keep the combination with the fewest presses where every button is pressed a whole,
+// non-negative number of times.
+
+func (d *Today) Variants(part string) []lib.Variant {
+	if part == "part2" {
+		return []lib.Variant{{Name: "linalg", Solve: d.part2LinearAlgebra}}
+	}
+	return nil
+}
+
+func (d *Today) part2LinearAlgebra(ctx context.Context) (string, error) {
+	total := 0
+	for i, machine := range d.machines {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+
+		presses, ok := minimumPresses(machine)
+		if !ok {
+			return "", fmt.Errorf("machine %d: %w", i, lib.ErrNoSolution)
+		}
+		total += presses
+	}
+
+	return strconv.Itoa(total), nil
+}
+
+// normalize divides the row by the gcd of its entries, so the numbers don't keep growing
+func normalize(row []int) {
+	divisor := 0
+	for _, v := range row {
+		divisor = intgr.GCD(divisor, intgr.Abs(v))
+	}
+	if divisor <= 1 {
+		return
+	}
+	for i := range row {
+		row[i] /= divisor
+	}
+}
+
+func minimumPresses(machine Machine) (int, bool) {
+	buttons := len(machine.wiringSchematics)
+	counters := len(machine.joltageRequirements)
+
+	// one row per counter: a column per button, then the target joltage
+	rows := make([][]int, counters)
+	for i, joltage := range machine.joltageRequirements {
+		rows[i] = make([]int, buttons+1)
+		rows[i][buttons] = joltage
+	}
+	// a button can't be pressed more times than the smallest joltage it adds to, and one that isn't
+	// wired to anything is never worth pressing
+	bounds := make([]int, buttons)
+	for j, button := range machine.wiringSchematics {
+		if len(button) > 0 {
+			bounds[j] = math.MaxInt
+		}
+		for _, i := range button {
+			rows[i][j] = 1
+			bounds[j] = min(bounds[j], machine.joltageRequirements[i])
+		}
+	}
+
+	// fraction free elimination keeps everything in integers
+	pivots := []int{}
+	isPivot := make([]bool, buttons)
+	for col := 0; col < buttons && len(pivots) < counters; col++ {
+		r := len(pivots)
+		found := -1
+		for i := r; i < counters; i++ {
+			if rows[i][col] != 0 {
+				found = i
+				break
+			}
+		}
+		if found < 0 {
+			continue
+		}
+		rows[r], rows[found] = rows[found], rows[r]
+
+		for i := range rows {
+			if i == r || rows[i][col] == 0 {
+				continue
+			}
+			f, g := rows[i][col], rows[r][col]
+			for k := range rows[i] {
+				rows[i][k] = rows[i][k]*g - rows[r][k]*f
+			}
+			normalize(rows[i])
+		}
+
+		pivots = append(pivots, col)
+		isPivot[col] = true
+	}
+
+	// the leftover rows are all zeros, so they have to have a zero target too
+	for _, row := range rows[len(pivots):] {
+		if row[buttons] != 0 {
+			return 0, false
+		}
+	}
+
+	free := []int{}
+	for col := range buttons {
+		if !isPivot[col] {
+			free = append(free, col)
+		}
+	}
+
+	best := math.MaxInt
+	presses := make([]int, buttons)
+	var try func(i int, sum int)
+	try = func(i int, sum int) {
+		// the pivot buttons can only add presses
+		if sum >= best {
+			return
+		}
+
+		if i < len(free) {
+			for count := 0; count <= bounds[free[i]]; count++ {
+				presses[free[i]] = count
+				try(i+1, sum+count)
+			}
+			return
+		}
+
+		for r, col := range pivots {
+			rest := rows[r][buttons]
+			for _, f := range free {
+				rest -= rows[r][f] * presses[f]
+			}
+			if rest%rows[r][col] != 0 || rest/rows[r][col] < 0 {
+				return
+			}
+			sum += rest / rows[r][col]
+		}
+		best = min(best, sum)
+	}
+	try(0, 0)
+
+	return best, best < math.MaxInt
+}
@@ -0,0 +1,26 @@
+package solution
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMinimumPresses(t *testing.T) {
+	for _, c := range []struct {
+		name     string
+		machine  Machine
+		expected int
+	}{
+		{"sample", Machine{wiringSchematics: []Button{{3}, {1, 3}, {2}, {2, 3}, {0, 2}, {0, 1}}, joltageRequirements: JoltageRequirements{3, 5, 4, 7}}, 10},
+		{"empty button", Machine{wiringSchematics: []Button{{}, {0}, {0, 1}}, joltageRequirements: JoltageRequirements{3, 1}}, 3},
+		{"only an empty button", Machine{wiringSchematics: []Button{{}}, joltageRequirements: JoltageRequirements{0}}, 0},
+	} {
+		presses, ok := minimumPresses(c.machine)
+		assert.True(t, ok, c.name)
+		assert.Equal(t, c.expected, presses, c.name)
+	}
+
+	_, ok := minimumPresses(Machine{wiringSchematics: []Button{{}}, joltageRequirements: JoltageRequirements{2}})
+	assert.False(t, ok, "an empty button can't reach a joltage")
+}
@@ -1,44 +1,38 @@
 package main
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
-)
 
-func TestPart1(t *testing.T) {
-	d := &Today{}
-	err := d.Init("sample.txt")
-	require.NoError(t, err)
+	"github.com/alex-whitney/advent-of-code-2025/lib"
+	"github.com/alex-whitney/advent-of-code-2025/lib/aoctest"
+)
 
-	result, err := d.Part1()
-	require.NoError(t, err)
-	assert.Equal(t, "50", result)
+func newDay() lib.Day {
+	return &Today{}
+}
 
-	d = &Today{}
-	err = d.Init("input.txt")
-	require.NoError(t, err)
+// the answers for sample.txt and input.txt are in answers.json
 
-	result, err = d.Part1()
-	require.NoError(t, err)
-	assert.Equal(t, "4777967538", result)
+func TestAnswers(t *testing.T) {
+	aoctest.Run(t, newDay, aoctest.AnswerCases(t, "."))
 }
 
-func TestPart2(t *testing.T) {
+func TestCancelled(t *testing.T) {
 	d := &Today{}
-	err := d.Init("sample.txt")
-	require.NoError(t, err)
-
-	result, err := d.Part2()
-	require.NoError(t, err)
-	assert.Equal(t, "24", result)
-
-	d = &Today{}
-	err = d.Init("input.txt")
-	require.NoError(t, err)
+	require.NoError(t, d.Init("sample.txt"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := d.Part1Context(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = d.Part2Context(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+}
 
-	result, err = d.Part2()
-	require.NoError(t, err)
-	assert.Equal(t, "1439894345", result)
+func BenchmarkAnswers(b *testing.B) {
+	aoctest.Bench(b, newDay, aoctest.AnswerCases(b, "."))
 }
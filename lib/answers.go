@@ -0,0 +1,150 @@
+package lib
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/urfave/cli/v2"
+)
+
+// AnswersFile is the name of the file in each day's directory that stores its accepted answers
+const AnswersFile = "answers.json"
+
+// Answers maps an input name (like "sample") to the accepted answer for each part ("part1", "part2")
+type Answers map[string]map[string]string
+
+// LoadAnswers reads the answers file from a day's directory. A missing file has no answers.
+func LoadAnswers(dir string) (Answers, error) {
+	data, err := os.ReadFile(filepath.Join(dir, AnswersFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return Answers{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	answers := Answers{}
+	err = json.Unmarshal(data, &answers)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", AnswersFile, err)
+	}
+	return answers, nil
+}
+
+// Save writes the answers file to a day's directory
+func (a Answers) Save(dir string) error {
+	data, err := json.MarshalIndent(a, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, AnswersFile), append(data, '\n'), 0644)
+}
+
+// Expected returns the accepted answer for a part of an input, if there is one
+func (a Answers) Expected(input string, part string) (string, bool) {
+	answer, ok := a[input][part]
+	return answer, ok
+}
+
+// Record stores the accepted answer for a part of an input
+func (a Answers) Record(input string, part string, answer string) {
+	if a[input] == nil {
+		a[input] = map[string]string{}
+	}
+	a[input][part] = answer
+}
+
+// Inputs returns the names of the inputs that have answers, sorted
+func (a Answers) Inputs() []string {
+	inputs := make([]string, 0, len(a))
+	for input := range a {
+		inputs = append(inputs, input)
+	}
+	sort.Strings(inputs)
+	return inputs
+}
+
+// verify runs the day against each input and compares the answers against the answers file in dir.
+// Without record, only the parts with a known answer are run. With record, every part is run and
+// answers for parts that didn't have one are saved. Returns the number of mismatches and failures,
+// and the exit status for the worst of them.
+func verify(ctx context.Context, d Day, number int, dir string, inputs []string, record bool) (int, int, error) {
+	answers, err := LoadAnswers(dir)
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(inputs) == 0 {
+		inputs = answers.Inputs()
+	}
+
+	failures, status := 0, 0
+	recorded := false
+	for _, input := range inputs {
+		_, has1 := answers.Expected(input, "part1")
+		_, has2 := answers.Expected(input, "part2")
+
+		command := "all"
+		if !record {
+			if !has1 && !has2 {
+				fmt.Printf("---- day %d %s: no known answers\n", number, input)
+				continue
+			} else if !has2 {
+				command = "part1"
+			} else if !has1 {
+				command = "part2"
+			}
+		}
+
+		results := runParts(ctx, d, number, NamedInput(dir, input), command, runOptions{}, nopReporter{})
+		status = max(status, runExitStatus(results))
+		for _, r := range results {
+			label := fmt.Sprintf("day %d %s %s", number, input, r.Part)
+			expected, ok := answers.Expected(input, r.Part)
+
+			switch {
+			case r.Failed():
+				fmt.Printf("FAIL %s: %s\n", label, r.Error)
+				failures++
+			case r.Part == "init":
+			case r.Skipped:
+				fmt.Printf("skip %s: no such part\n", label)
+			case !ok && record:
+				answers.Record(input, r.Part, r.Answer)
+				recorded = true
+				fmt.Printf("new  %s: %s (recorded)\n", label, r.Answer)
+			case !ok:
+				fmt.Printf("new  %s: %s (no known answer)\n", label, r.Answer)
+			case expected != r.Answer:
+				fmt.Printf("FAIL %s\n", label)
+				fmt.Printf("  - expected: %s\n", expected)
+				fmt.Printf("  + actual:   %s\n", r.Answer)
+				failures++
+				status = max(status, ExitSolverError)
+			default:
+				fmt.Printf("ok   %s: %s\n", label, r.Answer)
+			}
+		}
+	}
+
+	if recorded {
+		err = answers.Save(dir)
+		if err != nil {
+			return failures, status, err
+		}
+	}
+	return failures, status, nil
+}
+
+// verifyFlags are shared by the single and multi-day verify commands
+var verifyFlags = []cli.Flag{
+	&cli.BoolFlag{
+		Name:  "record",
+		Usage: "save answers for parts that don't have one yet",
+	},
+}
@@ -0,0 +1,69 @@
+package lib
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnswers(t *testing.T) {
+	dir := t.TempDir()
+
+	answers, err := LoadAnswers(dir)
+	require.NoError(t, err)
+	assert.Empty(t, answers)
+
+	answers.Record("sample", "part1", "50")
+	answers.Record("input", "part2", "24")
+	require.NoError(t, answers.Save(dir))
+
+	answers, err = LoadAnswers(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"input", "sample"}, answers.Inputs())
+
+	expected, ok := answers.Expected("sample", "part1")
+	assert.True(t, ok)
+	assert.Equal(t, "50", expected)
+
+	_, ok = answers.Expected("sample", "part2")
+	assert.False(t, ok)
+}
+
+func TestVerify(t *testing.T) {
+	dir := t.TempDir()
+
+	answers := Answers{}
+	answers.Record("sample", "part1", "Hello")
+	answers.Record("sample", "part2", "Universe")
+	require.NoError(t, answers.Save(dir))
+
+	ctx := context.Background()
+	failures, status, err := verify(ctx, &stubDay{}, 99, dir, nil, false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, failures)
+	assert.Equal(t, ExitSolverError, status)
+
+	// a day that can't parse its input exits like run does
+	failures, status, err = verify(ctx, &panicDay{}, 99, dir, nil, false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, failures)
+	assert.Equal(t, ExitInputError, status)
+
+	interrupted, cancel := context.WithCancelCause(ctx)
+	cancel(ErrInterrupted)
+	_, status, err = verify(interrupted, &stubDay{}, 99, dir, nil, false)
+	require.NoError(t, err)
+	assert.Equal(t, ExitInterrupted, status)
+
+	failures, status, err = verify(ctx, &stubDay{}, 99, dir, []string{"input"}, true)
+	require.NoError(t, err)
+	assert.Equal(t, 0, failures)
+	assert.Equal(t, 0, status)
+
+	answers, err = LoadAnswers(dir)
+	require.NoError(t, err)
+	expected, _ := answers.Expected("input", "part2")
+	assert.Equal(t, "World", expected)
+}
@@ -0,0 +1,405 @@
+package lib
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+// parseDay accepts a day as either "7" or "day7"
+func parseDay(arg string) (int, error) {
+	number, err := strconv.Atoi(strings.TrimPrefix(arg, "day"))
+	if err != nil {
+		return 0, fmt.Errorf("invalid day %q", arg)
+	}
+	return number, nil
+}
+
+// dayArg creates the day named by the command's first argument
+func dayArg(c *cli.Context) (int, Day, error) {
+	if c.Args().Len() == 0 {
+		return 0, nil, errors.New("a day is required")
+	}
+	number, err := parseDay(c.Args().Get(0))
+	if err != nil {
+		return 0, nil, err
+	}
+	d, err := NewDay(number)
+	return number, d, err
+}
+
+// selectedDays parses the days given as arguments, or returns every registered day if there are none
+func selectedDays(c *cli.Context) ([]int, error) {
+	if c.Args().Len() == 0 {
+		return Days(), nil
+	}
+
+	days := make([]int, c.Args().Len())
+	for i, arg := range c.Args().Slice() {
+		number, err := parseDay(arg)
+		if err != nil {
+			return nil, err
+		}
+		days[i] = number
+	}
+	return days, nil
+}
+
+// testDay runs both parts of a day against its sample input, printing one line per part. Returns
+// the exit status of the worst failure, or 0 if the day passed.
+func testDay(number int) int {
+	d, err := NewDay(number)
+	if err != nil {
+		fmt.Printf("day %d: %v\n", number, err)
+		return ExitSolverError
+	}
+
+	in := NamedInput(DayDir(number), "sample")
+	init := runPhase(number, "init", in, func() (string, error) {
+		return "", d.Init(in.Path)
+	})
+	if init.Failed() {
+		fmt.Printf("day %d: init failed: %s\n", number, init.Error)
+		fmt.Fprint(os.Stderr, init.Stack)
+		return init.ExitStatus
+	}
+
+	status := 0
+	for part, run := range []func() (string, error){d.Part1, d.Part2} {
+		r := runPhase(number, fmt.Sprintf("part%d", part+1), in, run)
+		if r.Skipped {
+			fmt.Printf("day %d part %d: no such part\n", number, part+1)
+		} else if r.Failed() {
+			fmt.Printf("day %d part %d: FAIL: %s\n", number, part+1, r.Error)
+			fmt.Fprint(os.Stderr, r.Stack)
+			status = max(status, r.ExitStatus)
+		} else {
+			fmt.Printf("day %d part %d: %s\n", number, part+1, r.Answer)
+		}
+	}
+
+	return status
+}
+
+// Main runs the multi-day command line for every day added with Register. Inputs are read
+// from each day's directory, so it should be run from the module root.
+func Main() {
+	app := &cli.App{
+		Name:                   "aoc",
+		Usage:                  "run Advent of Code solutions",
+		Flags:                  logFlags,
+		Before:                 configureLogging,
+		UseShortOptionHandling: true,
+		Commands: []*cli.Command{
+			{
+				Name:  "list",
+				Usage: "list the registered days",
+				Action: func(c *cli.Context) error {
+					for _, number := range Days() {
+						fmt.Printf("%2d  %s\n", number, DayDir(number))
+					}
+					return nil
+				},
+			},
+			{
+				Name:      "run",
+				Usage:     "run a day, or every day at once with \"run all\"",
+				ArgsUsage: "DAY|all [part1|part2|all] [INPUT]",
+				Flags:     runAllFlags,
+				Action: func(c *cli.Context) error {
+					command := "all"
+					if c.Args().Len() > 1 {
+						command = c.Args().Get(1)
+					}
+					if command != "part1" && command != "part2" && command != "all" {
+						return fmt.Errorf("unknown part %q", command)
+					}
+
+					if c.Args().Get(0) == "all" {
+						return runAllCommand(c, command, inputArg(c, 2))
+					}
+
+					number, d, err := dayArg(c)
+					if err != nil {
+						return err
+					}
+					return runCommand(c, d, number, DayDir(number), command, inputArg(c, 2))
+				},
+			},
+			{
+				Name:      "watch",
+				Usage:     "rebuild and rerun a day whenever its code or inputs change",
+				ArgsUsage: "DAY [part1|part2|all] [INPUT]",
+				Flags:     watchFlags,
+				Action: func(c *cli.Context) error {
+					number, _, err := dayArg(c)
+					if err != nil {
+						return err
+					}
+					return watchCommand(c, DayDir(number), 1)
+				},
+			},
+			{
+				Name:      "crosscheck",
+				Usage:     "run every solver for each of a day's parts and check that they agree",
+				ArgsUsage: "DAY [part1|part2|all] [INPUT]",
+				Flags:     crosscheckFlags,
+				Action: func(c *cli.Context) error {
+					number, d, err := dayArg(c)
+					if err != nil {
+						return err
+					}
+					return crosscheckCommand(c, d, number, DayDir(number), 1)
+				},
+			},
+			{
+				Name:      "bench",
+				Usage:     "time repeated runs of a day",
+				ArgsUsage: "DAY [INPUT]",
+				Flags:     benchFlags,
+				Action: func(c *cli.Context) error {
+					number, d, err := dayArg(c)
+					if err != nil {
+						return err
+					}
+
+					ctx, stop := interruptContext(c.Context)
+					defer stop()
+
+					opts := runOptions{timeout: c.Duration("timeout"), solver: c.String("solver")}
+					return bench(ctx, os.Stdout, d, number, DayDir(number), inputArg(c, 1), c.Int("count"), c.Int("warmup"), opts)
+				},
+			},
+			{
+				Name:      "verify",
+				Usage:     "check days against their answers.json",
+				ArgsUsage: "[DAY...]",
+				Flags:     verifyFlags,
+				Action: func(c *cli.Context) error {
+					days, err := selectedDays(c)
+					if err != nil {
+						return err
+					}
+
+					ctx, stop := interruptContext(c.Context)
+					defer stop()
+
+					failures, status := 0, 0
+					for _, number := range days {
+						d, err := NewDay(number)
+						if err != nil {
+							return err
+						}
+						n, s, err := verify(ctx, d, number, DayDir(number), nil, c.Bool("record"))
+						if err != nil {
+							return err
+						}
+						failures, status = failures+n, max(status, s)
+						if ctx.Err() != nil {
+							break
+						}
+					}
+					if failures > 0 {
+						return cli.Exit(fmt.Sprintf("%d answers did not match", failures), status)
+					}
+					return nil
+				},
+			},
+			{
+				Name:      "report",
+				Usage:     "run days against their inputs and summarize the stars, answers and times as markdown",
+				ArgsUsage: "[DAY...]",
+				Flags:     reportFlags,
+				Action: func(c *cli.Context) error {
+					days, err := selectedDays(c)
+					if err != nil {
+						return err
+					}
+
+					ctx, stop := interruptContext(c.Context)
+					defer stop()
+
+					opts := reportOptions{budget: c.Duration("budget"), mask: c.Bool("mask")}
+					timeout := c.Duration("timeout")
+					if !c.IsSet("timeout") {
+						timeout = opts.budget
+					}
+					return report(ctx, days, timeout, opts, c.String("readme"))
+				},
+			},
+			{
+				Name:      "history",
+				Usage:     "show how long past runs of days took and flag the parts that got slower",
+				ArgsUsage: "[DAY...]",
+				Flags:     historyFlags,
+				Action: func(c *cli.Context) error {
+					days, err := selectedDays(c)
+					if err != nil {
+						return err
+					}
+
+					dirs := make([]string, len(days))
+					for i, number := range days {
+						dirs[i] = DayDir(number)
+					}
+					return historyCommand(c, days, dirs)
+				},
+			},
+			{
+				Name:   "serve",
+				Usage:  "run days on inputs posted to POST /days/{n}/parts/{p} over HTTP",
+				Flags:  serveFlags,
+				Action: serveCommand,
+			},
+			{
+				Name:      "fetch",
+				Usage:     "download puzzle inputs that haven't been saved yet",
+				ArgsUsage: "[DAY...]",
+				Flags:     clientFlags,
+				Action: func(c *cli.Context) error {
+					days, err := selectedDays(c)
+					if err != nil {
+						return err
+					}
+					client, err := clientFromFlags(c)
+					if err != nil {
+						return err
+					}
+
+					for _, number := range days {
+						fetched, err := fetchInput(c.Context, client, number, DayDir(number))
+						if err != nil {
+							return fmt.Errorf("day %d: %w", number, err)
+						}
+						if fetched {
+							fmt.Printf("day %d: saved %s\n", number, filepath.Join(DayDir(number), "input.txt"))
+						} else {
+							fmt.Printf("day %d: already saved\n", number)
+						}
+					}
+					return nil
+				},
+			},
+			{
+				Name:      "encrypt",
+				Usage:     "encrypt days' input.txt to input.txt.enc, generating a key if there isn't one",
+				ArgsUsage: "[DAY...]",
+				Flags:     encryptFlags,
+				Action: func(c *cli.Context) error {
+					days, err := selectedDays(c)
+					if err != nil {
+						return err
+					}
+					return encryptCommand(c, days)
+				},
+			},
+			{
+				Name:      "decrypt",
+				Usage:     "decrypt days' input.txt.enc back to input.txt",
+				ArgsUsage: "[DAY...]",
+				Flags:     encryptFlags,
+				Action: func(c *cli.Context) error {
+					days, err := selectedDays(c)
+					if err != nil {
+						return err
+					}
+					return decryptCommand(c, days)
+				},
+			},
+			{
+				Name:      "submit",
+				Usage:     "submit an answer, running the day on its input if one isn't given",
+				ArgsUsage: "DAY part1|part2 [ANSWER]",
+				Flags:     submitFlags,
+				Action: func(c *cli.Context) error {
+					number, d, err := dayArg(c)
+					if err != nil {
+						return err
+					}
+					part := c.Args().Get(1)
+					if part != "part1" && part != "part2" {
+						return fmt.Errorf("unknown part %q", part)
+					}
+					client, err := clientFromFlags(c)
+					if err != nil {
+						return err
+					}
+
+					ctx, stop := interruptContext(c.Context)
+					defer stop()
+
+					answer := c.Args().Get(2)
+					if answer == "" {
+						results := runParts(ctx, d, number, NamedInput(DayDir(number), "input"), part, runOptions{}, nopReporter{})
+						r := results[len(results)-1]
+						if r.Failed() {
+							return cli.Exit(fmt.Sprintf("%s failed: %s", r.Part, r.Error), r.ExitStatus)
+						}
+						if r.Skipped {
+							return fmt.Errorf("day %d has no %s", number, r.Part)
+						}
+						answer = r.Answer
+					}
+
+					return submit(ctx, client, number, DayDir(number), part, answer, c.Bool("force"))
+				},
+			},
+			{
+				Name:      "new",
+				Usage:     "generate a new day from a template",
+				ArgsUsage: "DAY",
+				Flags:     newFlags,
+				Action: func(c *cli.Context) error {
+					if c.Args().Len() != 1 {
+						return errors.New("a day is required")
+					}
+					number, err := parseDay(c.Args().Get(0))
+					if err != nil {
+						return err
+					}
+
+					err = scaffold(number, c.String("template"))
+					if err != nil {
+						return err
+					}
+					fmt.Printf("created %s\n", DayDir(number))
+					return nil
+				},
+			},
+			{
+				Name:      "test",
+				Usage:     "run days against their sample input",
+				ArgsUsage: "[DAY...]",
+				Action: func(c *cli.Context) error {
+					days, err := selectedDays(c)
+					if err != nil {
+						return err
+					}
+
+					failures, status := 0, 0
+					for _, number := range days {
+						if dayStatus := testDay(number); dayStatus != 0 {
+							failures++
+							status = max(status, dayStatus)
+						}
+					}
+					if failures > 0 {
+						return cli.Exit(fmt.Sprintf("%d of %d days failed", failures, len(days)), status)
+					}
+					return nil
+				},
+			},
+		},
+	}
+
+	err := app.Run(os.Args)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,142 @@
+// Package aoctest runs a day's parts against tables of known answers in tests and benchmarks.
+package aoctest
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alex-whitney/advent-of-code-2025/lib"
+)
+
+// Case is the expected answers for one input
+type Case struct {
+	// Input is the name of the input file in the test's directory, without .txt, like "sample"
+	Input string
+	// Contents is used as the input instead of a file when it's set
+	Contents string
+	// Part1 and Part2 are the expected answers. Parts with an empty answer aren't run.
+	Part1 string
+	Part2 string
+}
+
+func (c Case) name() string {
+	if c.Input == "" {
+		return "inline"
+	}
+	return filepath.Base(c.Input)
+}
+
+// parts returns the expected answer for each part that has one
+func (c Case) parts() []lib.Pair[string, string] {
+	parts := []lib.Pair[string, string]{}
+	if c.Part1 != "" {
+		parts = append(parts, lib.NewPair("part1", c.Part1))
+	}
+	if c.Part2 != "" {
+		parts = append(parts, lib.NewPair("part2", c.Part2))
+	}
+	return parts
+}
+
+// path returns what to pass to Init, skipping the test if the input file isn't there (inputs
+// often aren't committed), or is encrypted without a key to read it
+func (c Case) path(tb testing.TB) string {
+	if c.Contents != "" {
+		name := lib.StringInput(c.Contents)
+		tb.Cleanup(func() { lib.ReleaseInput(name) })
+		return name
+	}
+
+	path := c.Input + ".txt"
+	if !lib.InputExists(path) {
+		tb.Skipf("%s isn't present", path)
+	}
+	if _, err := lib.ReadFile(path); errors.Is(err, lib.ErrNoInputKey) {
+		tb.Skipf("%s is encrypted and there's no key to read it", path)
+	}
+	return path
+}
+
+// AnswerCases loads the cases from the answers.json in dir, which is "." for the one next to the
+// test. The inputs are read from dir too.
+func AnswerCases(tb testing.TB, dir string) []Case {
+	answers, err := lib.LoadAnswers(dir)
+	require.NoError(tb, err)
+
+	cases := []Case{}
+	for _, input := range answers.Inputs() {
+		part1, _ := answers.Expected(input, "part1")
+		part2, _ := answers.Expected(input, "part2")
+		cases = append(cases, Case{Input: filepath.Join(dir, input), Part1: part1, Part2: part2})
+	}
+	return cases
+}
+
+func runPart(d lib.Day, part string) (string, error) {
+	if part == "part1" {
+		return d.Part1()
+	}
+	return d.Part2()
+}
+
+// Run checks each part of each case in a parallel subtest, named like "sample/part1". Every
+// subtest initializes its own day from newDay. Parts that return lib.ErrNoPart are skipped.
+func Run(t *testing.T, newDay func() lib.Day, cases []Case) {
+	for _, c := range cases {
+		for _, part := range c.parts() {
+			t.Run(c.name()+"/"+part.Left, func(t *testing.T) {
+				t.Parallel()
+
+				d := newDay()
+				err := d.Init(c.path(t))
+				require.NoError(t, err)
+
+				result, err := runPart(d, part.Left)
+				if errors.Is(err, lib.ErrNoPart) {
+					t.Skip("the puzzle has no " + part.Left)
+				}
+				require.NoError(t, err)
+				assert.Equal(t, part.Right, result)
+			})
+		}
+	}
+}
+
+// Bench benchmarks each part of each case that Run would check. Parts can change the day's state,
+// so every iteration runs on a freshly initialized day, or a clone of one for days that implement
+// lib.Cloner. Neither Init nor Clone is included in the timings.
+func Bench(b *testing.B, newDay func() lib.Day, cases []Case) {
+	for _, c := range cases {
+		for _, part := range c.parts() {
+			b.Run(c.name()+"/"+part.Left, func(b *testing.B) {
+				path := c.path(b)
+				initialized := func() lib.Day {
+					d := newDay()
+					err := d.Init(path)
+					require.NoError(b, err)
+					return d
+				}
+				fresh := initialized
+				if cloner, ok := initialized().(lib.Cloner); ok {
+					fresh = func() lib.Day { return cloner.Clone() }
+				}
+
+				for b.Loop() {
+					b.StopTimer()
+					d := fresh()
+					b.StartTimer()
+
+					_, err := runPart(d, part.Left)
+					if errors.Is(err, lib.ErrNoPart) {
+						b.Skip("the puzzle has no " + part.Left)
+					}
+					require.NoError(b, err)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package aoctest
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/alex-whitney/advent-of-code-2025/lib"
+)
+
+// lineDay answers with its input's first line for part 1 and doesn't have a part 2
+type lineDay struct {
+	lines []string
+}
+
+func (d *lineDay) Init(input string) error {
+	contents, err := lib.ReadFile(input)
+	d.lines = strings.Split(contents, "\n")
+	return err
+}
+
+func (d *lineDay) Part1() (string, error) {
+	return d.lines[0], nil
+}
+
+func (d *lineDay) Part2() (string, error) {
+	return "", lib.ErrNoPart
+}
+
+func newLineDay() lib.Day {
+	return &lineDay{}
+}
+
+// poppingDay's part 1 takes the first line off its input, so it fails if it runs twice without Init
+type poppingDay struct {
+	lineDay
+}
+
+func (d *poppingDay) Part1() (string, error) {
+	if len(d.lines) == 0 {
+		return "", errors.New("part 1 ran twice")
+	}
+	first := d.lines[0]
+	d.lines = d.lines[1:]
+	return first, nil
+}
+
+func TestRun(t *testing.T) {
+	Run(t, newLineDay, []Case{
+		{Contents: "first\nsecond", Part1: "first", Part2: "unused"},
+		{Input: "missing", Part1: "first"},
+	})
+}
+
+func TestAnswerCases(t *testing.T) {
+	dir := t.TempDir()
+	answers := lib.Answers{}
+	answers.Record("sample", "part1", "first")
+	answers.Record("sample2", "part2", "2")
+	require.NoError(t, answers.Save(dir))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "sample.txt"), []byte("first\nsecond"), 0644))
+
+	cases := AnswerCases(t, dir)
+	assert.Equal(t, []Case{
+		{Input: filepath.Join(dir, "sample"), Part1: "first"},
+		{Input: filepath.Join(dir, "sample2"), Part2: "2"},
+	}, cases)
+	Run(t, newLineDay, cases)
+}
+
+func TestCaseParts(t *testing.T) {
+	c := Case{Input: "sample", Part2: "2"}
+	assert.Equal(t, "sample", c.name())
+	assert.Equal(t, []lib.Pair[string, string]{lib.NewPair("part2", "2")}, c.parts())
+	assert.Equal(t, "inline", Case{Contents: "x"}.name())
+}
+
+func BenchmarkBench(b *testing.B) {
+	Bench(b, func() lib.Day { return &poppingDay{} }, []Case{{Contents: "first", Part1: "first"}})
+}
@@ -0,0 +1,169 @@
+package lib
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+// phaseStats collects the measurements of one phase (Init, Part1 or Part2) across bench runs
+type phaseStats struct {
+	name      string
+	durations []time.Duration
+	allocs    uint64
+	bytes     uint64
+	// skipped is set for a part the puzzle doesn't have (see ErrNoPart)
+	skipped bool
+}
+
+func (s *phaseStats) min() time.Duration {
+	return slices.Min(s.durations)
+}
+
+// percentile returns the nearest-rank percentile p (0-100) of the recorded durations
+func (s *phaseStats) percentile(p int) time.Duration {
+	sorted := slices.Clone(s.durations)
+	slices.Sort(sorted)
+
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+func (s *phaseStats) allocsPerRun() uint64 {
+	return s.allocs / uint64(len(s.durations))
+}
+
+func (s *phaseStats) bytesPerRun() uint64 {
+	return s.bytes / uint64(len(s.durations))
+}
+
+// measure runs f, returning the number and size of heap allocations it made
+func measure(f func()) (uint64, uint64) {
+	var before, after runtime.MemStats
+
+	runtime.ReadMemStats(&before)
+	f()
+	runtime.ReadMemStats(&after)
+
+	return after.Mallocs - before.Mallocs, after.TotalAlloc - before.TotalAlloc
+}
+
+// benchDay returns the instance of the day to use for a run: a new one for every run after the
+// first, so nothing one run leaves behind (like a cache) speeds up the next
+func benchDay(d Day, number int, run int) Day {
+	if run == 0 {
+		return d
+	}
+	fresh, err := NewDay(number)
+	if err != nil {
+		// days run on their own aren't always registered, so settle for initializing it again
+		return d
+	}
+	return fresh
+}
+
+// bench initializes and runs both parts of the day warmup+count times, and prints statistics for
+// the last count runs to w. The parts run the same way as they do for run, with the solver and
+// timeout from opts. A part the puzzle doesn't have is shown as "-".
+func bench(ctx context.Context, w io.Writer, d Day, number int, dir string, file string, count int, warmup int, opts runOptions) error {
+	if count < 1 {
+		return fmt.Errorf("count must be at least 1, got %d", count)
+	}
+	err := checkSolver(d, "all", opts.solver)
+	if err != nil {
+		return err
+	}
+
+	in := Input{Name: file, Path: filepath.Join(dir, file+".txt")}
+	phases := []*phaseStats{{name: "Init"}, {name: "Part1"}, {name: "Part2"}}
+
+	for run := 0; run < warmup+count; run++ {
+		day := benchDay(d, number, run)
+		for _, phase := range phases {
+			part := strings.ToLower(phase.name)
+			var solve func(context.Context) (string, error)
+			var timeout time.Duration
+			if part == "init" {
+				solve = func(context.Context) (string, error) { return "", day.Init(in.Path) }
+			} else {
+				pd, err := partDay(day, number, in, false, true)
+				if err != nil {
+					return err
+				}
+				solve, timeout = findVariant(pd, part, opts.solver).Solve, opts.timeout
+			}
+
+			var r Result
+			allocs, bytes := measure(func() {
+				r = runPhase(number, part, in, func() (string, error) {
+					return runWithContext(ctx, solve, timeout)
+				})
+			})
+			if r.Skipped {
+				phase.skipped = true
+				continue
+			}
+			if r.Failed() {
+				if r.Stack != "" {
+					fmt.Fprintf(os.Stderr, "\n%s", r.Stack)
+				}
+				return cli.Exit(fmt.Sprintf("%s failed: %s", phase.name, r.Error), r.ExitStatus)
+			}
+
+			if run < warmup {
+				continue
+			}
+			phase.durations = append(phase.durations, r.Duration())
+			phase.allocs += allocs
+			phase.bytes += bytes
+		}
+	}
+
+	fmt.Fprintf(w, "%d runs after %d warmup\n", count, warmup)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "phase\tmin\tmedian\tp95\tallocs/run\tbytes/run")
+	for _, phase := range phases {
+		if phase.skipped {
+			fmt.Fprintf(tw, "%s\t-\t-\t-\t-\t-\n", phase.name)
+			continue
+		}
+		fmt.Fprintf(tw, "%s\t%v\t%v\t%v\t%d\t%d\n", phase.name, phase.min(), phase.percentile(50),
+			phase.percentile(95), phase.allocsPerRun(), phase.bytesPerRun())
+	}
+	return tw.Flush()
+}
+
+// benchFlags are shared by the single and multi-day bench commands
+var benchFlags = []cli.Flag{
+	&cli.IntFlag{
+		Name:    "count",
+		Aliases: []string{"n"},
+		Value:   10,
+		Usage:   "number of measured runs",
+	},
+	&cli.IntFlag{
+		Name:  "warmup",
+		Value: 1,
+		Usage: "number of runs to discard before measuring",
+	},
+	&cli.DurationFlag{
+		Name:  "timeout",
+		Usage: "stop each part after this long, e.g. 30s",
+	},
+	&cli.StringFlag{
+		Name:  "solver",
+		Usage: "run each part with the solver called `NAME`, for days that have more than one",
+	},
+}
@@ -0,0 +1,90 @@
+package lib
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+func TestPercentile(t *testing.T) {
+	s := &phaseStats{}
+	for i := 20; i >= 1; i-- {
+		s.durations = append(s.durations, time.Duration(i)*time.Millisecond)
+	}
+
+	assert.Equal(t, 1*time.Millisecond, s.min())
+	assert.Equal(t, 10*time.Millisecond, s.percentile(50))
+	assert.Equal(t, 19*time.Millisecond, s.percentile(95))
+	assert.Equal(t, 20*time.Millisecond, s.percentile(100))
+}
+
+func TestBenchNoPart(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "sample.txt"), []byte("one two"), 0644))
+
+	var out strings.Builder
+	err := bench(context.Background(), &out, NewTypedDay[[]string, int](wordCount{}), 99, dir, "sample", 2, 1, runOptions{})
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), "2 runs after 1 warmup")
+	assert.Regexp(t, `Part2\s+-\s+-\s+-\s+-\s+-`, out.String())
+}
+
+func TestBenchPanic(t *testing.T) {
+	var out strings.Builder
+	err := bench(context.Background(), &out, &panicDay{}, 99, t.TempDir(), "input", 1, 0, runOptions{})
+	var exit cli.ExitCoder
+	require.ErrorAs(t, err, &exit)
+	assert.Equal(t, ExitInputError, exit.ExitCode())
+	assert.Contains(t, err.Error(), "Init failed: panic")
+}
+
+// onceDay's part 1 fails if it's run twice on the same instance
+type onceDay struct {
+	stubDay
+	ran bool
+}
+
+func (d *onceDay) Part1() (string, error) {
+	if d.ran {
+		return "", errors.New("already ran")
+	}
+	d.ran = true
+	return d.stubDay.Part1()
+}
+
+func TestBenchFreshDay(t *testing.T) {
+	created := 0
+	Register(99, func() Day {
+		created++
+		return &onceDay{}
+	})
+	defer delete(registry, 99)
+
+	var out strings.Builder
+	require.NoError(t, bench(context.Background(), &out, &onceDay{}, 99, t.TempDir(), "input", 2, 1, runOptions{}))
+	assert.Equal(t, 2, created, "every run after the first should get a new instance")
+}
+
+func TestBenchOptions(t *testing.T) {
+	var out strings.Builder
+	err := bench(context.Background(), &out, &variantDay{}, 99, t.TempDir(), "input", 1, 0, runOptions{solver: "fast"})
+	require.NoError(t, err)
+	assert.EqualError(t, bench(context.Background(), &out, &variantDay{}, 99, t.TempDir(), "input", 1, 0, runOptions{solver: "slow"}),
+		`unknown solver "slow", the choices are: default, fast`)
+
+	d := &slowDay{stopped: make(chan struct{})}
+	err = bench(context.Background(), &out, d, 99, t.TempDir(), "input", 1, 0, runOptions{timeout: 10 * time.Millisecond})
+	var exit cli.ExitCoder
+	require.ErrorAs(t, err, &exit)
+	assert.Equal(t, ExitTimeout, exit.ExitCode())
+	assert.Contains(t, err.Error(), "Part2 failed: timed out after 10ms")
+	<-d.stopped
+}
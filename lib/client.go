@@ -0,0 +1,209 @@
+package lib
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	// DefaultBaseURL is where puzzles are fetched from and submitted to
+	DefaultBaseURL = "https://adventofcode.com"
+	// DefaultYear is the event these solutions are for
+	DefaultYear = 2025
+
+	userAgent = "github.com/alex-whitney/advent-of-code-2025 by alex-whitney"
+)
+
+var (
+	// ErrNoSession means no session token was configured
+	ErrNoSession = errors.New("no session token: set AOC_SESSION or \"session\" in the config file")
+	// ErrSessionExpired means the site rejected the session token
+	ErrSessionExpired = errors.New("session token was rejected, it has probably expired: log in again and copy the new session cookie")
+	// ErrNotUnlocked means the puzzle isn't available yet
+	ErrNotUnlocked = errors.New("puzzle isn't unlocked yet")
+	// ErrRateLimited means the site asked us to slow down
+	ErrRateLimited = errors.New("rate limited")
+)
+
+// Config holds the settings for talking to the puzzle site. It's read from config.json in the
+// user's config directory (e.g. ~/.config/aoc/config.json), and the AOC_SESSION, AOC_BASE_URL and
+// AOC_YEAR environment variables override it.
+type Config struct {
+	Session string `json:"session"`
+	BaseURL string `json:"base_url"`
+	Year    int    `json:"year"`
+}
+
+// configPath is the location of the config file
+func configPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "aoc", "config.json"), nil
+}
+
+// LoadConfig reads the config file and environment variables, filling in defaults
+func LoadConfig() (Config, error) {
+	cfg := Config{}
+
+	path, err := configPath()
+	if err == nil {
+		data, err := os.ReadFile(path)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return cfg, err
+		}
+		if err == nil {
+			err = json.Unmarshal(data, &cfg)
+			if err != nil {
+				return cfg, fmt.Errorf("invalid %s: %w", path, err)
+			}
+		}
+	}
+
+	if session := os.Getenv("AOC_SESSION"); session != "" {
+		cfg.Session = session
+	}
+	if baseURL := os.Getenv("AOC_BASE_URL"); baseURL != "" {
+		cfg.BaseURL = baseURL
+	}
+	if year := os.Getenv("AOC_YEAR"); year != "" {
+		cfg.Year, err = strconv.Atoi(year)
+		if err != nil {
+			return cfg, fmt.Errorf("invalid AOC_YEAR %q", year)
+		}
+	}
+
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = DefaultBaseURL
+	}
+	if cfg.Year == 0 {
+		cfg.Year = DefaultYear
+	}
+	return cfg, nil
+}
+
+// Client talks to the puzzle site
+type Client struct {
+	cfg  Config
+	http *http.Client
+}
+
+func NewClient(cfg Config) *Client {
+	cfg.BaseURL = strings.TrimSuffix(cfg.BaseURL, "/")
+	return &Client{
+		cfg:  cfg,
+		http: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// do sends a request for a day's page with the session cookie, returning the body of a successful
+// response. Error statuses are turned into ErrSessionExpired, ErrNotUnlocked or ErrRateLimited.
+func (c *Client) do(req *http.Request) (string, error) {
+	if c.cfg.Session == "" {
+		return "", ErrNoSession
+	}
+	req.AddCookie(&http.Cookie{Name: "session", Value: c.cfg.Session})
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return string(body), nil
+	case http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden:
+		return "", ErrSessionExpired
+	case http.StatusNotFound:
+		return "", ErrNotUnlocked
+	case http.StatusTooManyRequests:
+		if retry := resp.Header.Get("Retry-After"); retry != "" {
+			return "", fmt.Errorf("%w: retry after %ss", ErrRateLimited, retry)
+		}
+		return "", ErrRateLimited
+	default:
+		return "", fmt.Errorf("unexpected response %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+}
+
+func (c *Client) dayURL(number int) string {
+	return fmt.Sprintf("%s/%d/day/%d", c.cfg.BaseURL, c.cfg.Year, number)
+}
+
+// FetchInput downloads a day's puzzle input. The trailing newline is removed, since the days'
+// parsers expect the input to end on the last line.
+func (c *Client) FetchInput(ctx context.Context, number int) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.dayURL(number)+"/input", nil)
+	if err != nil {
+		return "", err
+	}
+
+	body, err := c.do(req)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(body, "\n"), nil
+}
+
+// fetchInput saves a day's input to dir/input.txt, unless it's already there or encrypted. An
+// empty file (as left by the new command) doesn't count. Returns whether the input was downloaded.
+func fetchInput(ctx context.Context, client *Client, number int, dir string) (bool, error) {
+	path := filepath.Join(dir, "input.txt")
+	if info, err := os.Stat(path); err == nil && info.Size() > 0 {
+		return false, nil
+	}
+	if _, err := os.Stat(path + EncryptedSuffix); err == nil {
+		return false, nil
+	}
+
+	input, err := client.FetchInput(ctx, number)
+	if err != nil {
+		return false, err
+	}
+
+	err = os.MkdirAll(dir, 0755)
+	if err != nil {
+		return false, err
+	}
+	return true, os.WriteFile(path, []byte(input), 0644)
+}
+
+// clientFlags are shared by the commands that talk to the puzzle site
+var clientFlags = []cli.Flag{
+	&cli.StringFlag{
+		Name:  "base-url",
+		Usage: "`URL` of the puzzle site, overriding the config file",
+	},
+}
+
+// clientFromFlags creates a Client from the config, with any overrides from clientFlags
+func clientFromFlags(c *cli.Context) (*Client, error) {
+	cfg, err := LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	if c.IsSet("base-url") {
+		cfg.BaseURL = c.String("base-url")
+	}
+	return NewClient(cfg), nil
+}
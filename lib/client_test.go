@@ -0,0 +1,108 @@
+package lib
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSite stands in for the puzzle site. Day 1 is unlocked, day 2 isn't, and day 3 is rate limited.
+func fakeSite(t *testing.T, requests *int) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /2025/day/{day}/input", func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+
+		cookie, err := r.Cookie("session")
+		if err != nil || cookie.Value != "valid" {
+			http.Error(w, "Puzzle inputs differ by user.  Please log in to get your puzzle input.", http.StatusBadRequest)
+			return
+		}
+
+		switch r.PathValue("day") {
+		case "1":
+			w.Write([]byte("L68\nR48\n"))
+		case "3":
+			w.Header().Set("Retry-After", "60")
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			http.Error(w, "Please don't repeatedly request this endpoint before it unlocks!", http.StatusNotFound)
+		}
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestFetchInput(t *testing.T) {
+	requests := 0
+	server := fakeSite(t, &requests)
+	client := NewClient(Config{Session: "valid", BaseURL: server.URL, Year: 2025})
+	dir := filepath.Join(t.TempDir(), "day1")
+
+	fetched, err := fetchInput(context.Background(), client, 1, dir)
+	require.NoError(t, err)
+	assert.True(t, fetched)
+
+	contents, err := os.ReadFile(filepath.Join(dir, "input.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "L68\nR48", string(contents))
+
+	// cached, so there's no second request
+	fetched, err = fetchInput(context.Background(), client, 1, dir)
+	require.NoError(t, err)
+	assert.False(t, fetched)
+	assert.Equal(t, 1, requests)
+}
+
+func TestFetchInputErrors(t *testing.T) {
+	requests := 0
+	server := fakeSite(t, &requests)
+	ctx := context.Background()
+
+	client := NewClient(Config{Session: "valid", BaseURL: server.URL, Year: 2025})
+	_, err := client.FetchInput(ctx, 2)
+	assert.ErrorIs(t, err, ErrNotUnlocked)
+
+	_, err = client.FetchInput(ctx, 3)
+	assert.ErrorIs(t, err, ErrRateLimited)
+	assert.ErrorContains(t, err, "retry after 60s")
+
+	client = NewClient(Config{Session: "expired", BaseURL: server.URL, Year: 2025})
+	_, err = client.FetchInput(ctx, 1)
+	assert.ErrorIs(t, err, ErrSessionExpired)
+
+	client = NewClient(Config{BaseURL: server.URL, Year: 2025})
+	_, err = client.FetchInput(ctx, 1)
+	assert.ErrorIs(t, err, ErrNoSession)
+	// no request is made without a session
+	assert.Equal(t, 3, requests)
+}
+
+func TestLoadConfig(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("AOC_BASE_URL", "")
+	t.Setenv("AOC_YEAR", "")
+
+	path, err := configPath()
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(`{"session": "from-file", "year": 2024}`), 0600))
+
+	t.Setenv("AOC_SESSION", "")
+	cfg, err := LoadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, Config{Session: "from-file", BaseURL: DefaultBaseURL, Year: 2024}, cfg)
+
+	t.Setenv("AOC_SESSION", "from-env")
+	cfg, err = LoadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "from-env", cfg.Session)
+}
@@ -0,0 +1,97 @@
+package lib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+)
+
+var (
+	// ErrTimeout is the cause of a part being stopped by --timeout
+	ErrTimeout = errors.New("timed out")
+	// ErrInterrupted is the cause of a part being stopped by SIGINT
+	ErrInterrupted = errors.New("interrupted")
+)
+
+// ContextDay can be implemented by days with long running parts. The runner calls Part1Context and
+// Part2Context instead of Part1 and Part2, and cancels the context on timeout or SIGINT. Solvers
+// should check ctx.Done() in their loops and return early once it's closed.
+type ContextDay interface {
+	Day
+	Part1Context(ctx context.Context) (string, error)
+	Part2Context(ctx context.Context) (string, error)
+}
+
+// interruptContext returns a context that's cancelled with ErrInterrupted on the first SIGINT.
+// A second SIGINT kills the process as usual.
+func interruptContext(parent context.Context) (context.Context, func()) {
+	ctx, cancel := context.WithCancelCause(parent)
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt)
+	go func() {
+		select {
+		case <-signals:
+			cancel(ErrInterrupted)
+		case <-ctx.Done():
+		}
+		signal.Stop(signals)
+	}()
+
+	return ctx, func() {
+		cancel(nil)
+	}
+}
+
+// partSolver returns the function that runs one part of the day, handing the context to days that
+// accept one
+func partSolver(d Day, part string) func(ctx context.Context) (string, error) {
+	cd, ok := d.(ContextDay)
+	switch {
+	case part == "part1" && ok:
+		return cd.Part1Context
+	case part == "part1":
+		return func(context.Context) (string, error) { return d.Part1() }
+	case ok:
+		return cd.Part2Context
+	default:
+		return func(context.Context) (string, error) { return d.Part2() }
+	}
+}
+
+// runWithContext runs one part of a day with solve (see partSolver), giving up once ctx is done or
+// the timeout (if non-zero) passes. Days that don't implement ContextDay can't be stopped, so an
+// abandoned part keeps running in the background until the process exits.
+func runWithContext(ctx context.Context, solve func(ctx context.Context) (string, error), timeout time.Duration) (string, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(ctx, timeout, fmt.Errorf("%w after %v", ErrTimeout, timeout))
+		defer cancel()
+	}
+	if ctx.Err() != nil {
+		return "", context.Cause(ctx)
+	}
+
+	type outcome struct {
+		answer string
+		err    error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		answer, err := callSafely(func() (string, error) { return solve(ctx) })
+		done <- outcome{answer, err}
+	}()
+
+	select {
+	case o := <-done:
+		if o.err != nil && ctx.Err() != nil && errors.Is(o.err, ctx.Err()) {
+			return "", context.Cause(ctx)
+		}
+		return o.answer, o.err
+	case <-ctx.Done():
+		return "", context.Cause(ctx)
+	}
+}
@@ -0,0 +1,50 @@
+package lib
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// slowDay's part 2 runs until it's cancelled
+type slowDay struct {
+	stubDay
+	stopped chan struct{}
+}
+
+func (d *slowDay) Part1Context(ctx context.Context) (string, error) {
+	return d.Part1()
+}
+
+func (d *slowDay) Part2Context(ctx context.Context) (string, error) {
+	<-ctx.Done()
+	close(d.stopped)
+	return "", ctx.Err()
+}
+
+func TestRunWithContextTimeout(t *testing.T) {
+	d := &slowDay{stopped: make(chan struct{})}
+
+	answer, err := runWithContext(context.Background(), partSolver(d, "part1"), time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, "Hello", answer)
+
+	_, err = runWithContext(context.Background(), partSolver(d, "part2"), 10*time.Millisecond)
+	assert.ErrorIs(t, err, ErrTimeout)
+
+	select {
+	case <-d.stopped:
+	case <-time.After(time.Second):
+		t.Fatal("part 2 was not cancelled")
+	}
+}
+
+func TestRunWithContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	cancel(ErrInterrupted)
+
+	_, err := runWithContext(ctx, partSolver(&stubDay{}, "part1"), 0)
+	assert.ErrorIs(t, err, ErrInterrupted)
+}
@@ -0,0 +1,284 @@
+package lib
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+)
+
+// EncryptedSuffix is added to the name of an encrypted input, e.g. input.txt.enc
+const EncryptedSuffix = ".enc"
+
+// inputKeySize is the length of the key in bytes, for AES-256
+const inputKeySize = 32
+
+// encryptedMagic starts every encrypted input, so other files aren't mistaken for one. It's also
+// authenticated along with the input, so the format can't be swapped out from under it.
+var encryptedMagic = []byte("aoc-enc1")
+
+// ErrNoInputKey means an input is encrypted but there's no key to decrypt it with
+var ErrNoInputKey = errors.New("no input key: set AOC_INPUT_KEY, or save it to the file named by AOC_INPUT_KEY_FILE (input.key in the config directory by default)")
+
+// inputKeyPath is where the key is read from when AOC_INPUT_KEY isn't set: AOC_INPUT_KEY_FILE, or
+// input.key next to the config file (e.g. ~/.config/aoc/input.key)
+func inputKeyPath() (string, error) {
+	if path := os.Getenv("AOC_INPUT_KEY_FILE"); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "aoc", "input.key"), nil
+}
+
+// loadInputKey reads the key for encrypted inputs, written as 64 hex characters, from the
+// AOC_INPUT_KEY environment variable or the key file
+func loadInputKey() ([]byte, error) {
+	encoded, source := os.Getenv("AOC_INPUT_KEY"), "AOC_INPUT_KEY"
+	if encoded == "" {
+		path, err := inputKeyPath()
+		if err != nil {
+			return nil, ErrNoInputKey
+		}
+		data, err := os.ReadFile(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, ErrNoInputKey
+		}
+		if err != nil {
+			return nil, err
+		}
+		encoded, source = string(data), path
+	}
+
+	key, err := hex.DecodeString(strings.TrimSpace(encoded))
+	if err != nil || len(key) != inputKeySize {
+		return nil, fmt.Errorf("invalid input key in %s: it should be %d hex characters", source, 2*inputKeySize)
+	}
+	return key, nil
+}
+
+// generateInputKey saves a new random key to path, which mustn't exist yet
+func generateInputKey(path string) ([]byte, error) {
+	key := make([]byte, inputKeySize)
+	_, err := rand.Read(key)
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+	_, err = f.WriteString(hex.EncodeToString(key) + "\n")
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return key, f.Close()
+}
+
+func newInputCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// encryptInput seals plaintext with AES-GCM. The result is the magic, then a random nonce, then
+// the ciphertext and its tag.
+func encryptInput(key []byte, plaintext []byte) ([]byte, error) {
+	aead, err := newInputCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return nil, err
+	}
+	out := append(slices.Clone(encryptedMagic), nonce...)
+	return aead.Seal(out, nonce, plaintext, encryptedMagic), nil
+}
+
+// decryptInput opens an input sealed by encryptInput
+func decryptInput(key []byte, data []byte) ([]byte, error) {
+	aead, err := newInputCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	sealed, ok := bytes.CutPrefix(data, encryptedMagic)
+	if !ok || len(sealed) < aead.NonceSize() {
+		return nil, errors.New("not an encrypted input")
+	}
+	nonce, sealed := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, sealed, encryptedMagic)
+	if err != nil {
+		return nil, errors.New("couldn't decrypt the input, the key is probably wrong")
+	}
+	return plaintext, nil
+}
+
+// readEncrypted reads and decrypts the encrypted copy of path. The error is fs.ErrNotExist if
+// there isn't one.
+func readEncrypted(path string) (string, error) {
+	data, err := os.ReadFile(path + EncryptedSuffix)
+	if err != nil {
+		return "", err
+	}
+
+	key, err := loadInputKey()
+	if err != nil {
+		return "", fmt.Errorf("%s is encrypted: %w", path+EncryptedSuffix, err)
+	}
+	plaintext, err := decryptInput(key, data)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", path+EncryptedSuffix, err)
+	}
+	return string(plaintext), nil
+}
+
+// InputExists reports whether there's a file at path, or an encrypted copy of one
+func InputExists(path string) bool {
+	for _, p := range []string{path, path + EncryptedSuffix} {
+		if _, err := os.Stat(p); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// encryptDayInput encrypts dir/input.txt to dir/input.txt.enc, removing the original unless keep
+// is set. Returns false if there was no input to encrypt.
+func encryptDayInput(key []byte, dir string, keep bool) (bool, error) {
+	path := filepath.Join(dir, "input.txt")
+	plaintext, err := os.ReadFile(path)
+	// the new command leaves an empty input.txt behind
+	if errors.Is(err, fs.ErrNotExist) || (err == nil && len(plaintext) == 0) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	data, err := encryptInput(key, plaintext)
+	if err != nil {
+		return false, err
+	}
+	err = os.WriteFile(path+EncryptedSuffix, data, 0644)
+	if err != nil {
+		return false, err
+	}
+	if keep {
+		return true, nil
+	}
+	return true, os.Remove(path)
+}
+
+// decryptDayInput decrypts dir/input.txt.enc back to dir/input.txt, removing the encrypted copy
+// unless keep is set. It won't overwrite an input.txt with different contents. Returns false if
+// there was no encrypted input.
+func decryptDayInput(key []byte, dir string, keep bool) (bool, error) {
+	path := filepath.Join(dir, "input.txt")
+	data, err := os.ReadFile(path + EncryptedSuffix)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	plaintext, err := decryptInput(key, data)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", path+EncryptedSuffix, err)
+	}
+	existing, err := os.ReadFile(path)
+	if err == nil && len(existing) > 0 && !bytes.Equal(existing, plaintext) {
+		return false, fmt.Errorf("%s already exists with different contents, move it out of the way first", path)
+	}
+
+	err = os.WriteFile(path, plaintext, 0644)
+	if err != nil {
+		return false, err
+	}
+	if keep {
+		return true, nil
+	}
+	return true, os.Remove(path + EncryptedSuffix)
+}
+
+// encryptCommand encrypts the inputs of the given days, generating a key first if there isn't one
+func encryptCommand(c *cli.Context, days []int) error {
+	key, err := loadInputKey()
+	if errors.Is(err, ErrNoInputKey) {
+		var path string
+		path, err = inputKeyPath()
+		if err != nil {
+			return err
+		}
+		key, err = generateInputKey(path)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("saved a new input key to %s, keep a copy somewhere safe since the inputs can't be read without it\n", path)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, number := range days {
+		encrypted, err := encryptDayInput(key, DayDir(number), c.Bool("keep"))
+		if err != nil {
+			return fmt.Errorf("day %d: %w", number, err)
+		}
+		if encrypted {
+			fmt.Printf("day %d: encrypted %s\n", number, filepath.Join(DayDir(number), "input.txt"+EncryptedSuffix))
+		}
+	}
+	return nil
+}
+
+// decryptCommand decrypts the inputs of the given days
+func decryptCommand(c *cli.Context, days []int) error {
+	key, err := loadInputKey()
+	if err != nil {
+		return err
+	}
+
+	for _, number := range days {
+		decrypted, err := decryptDayInput(key, DayDir(number), c.Bool("keep"))
+		if err != nil {
+			return fmt.Errorf("day %d: %w", number, err)
+		}
+		if decrypted {
+			fmt.Printf("day %d: decrypted %s\n", number, filepath.Join(DayDir(number), "input.txt"))
+		}
+	}
+	return nil
+}
+
+// encryptFlags are shared by the encrypt and decrypt commands
+var encryptFlags = []cli.Flag{
+	&cli.BoolFlag{
+		Name:  "keep",
+		Usage: "keep the original file instead of removing it",
+	},
+}
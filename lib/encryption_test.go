@@ -0,0 +1,132 @@
+package lib
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// useInputKey points the input key at a new key file in a temporary directory
+func useInputKey(t *testing.T) []byte {
+	path := filepath.Join(t.TempDir(), "input.key")
+	t.Setenv("AOC_INPUT_KEY", "")
+	t.Setenv("AOC_INPUT_KEY_FILE", path)
+
+	key, err := generateInputKey(path)
+	require.NoError(t, err)
+	return key
+}
+
+func TestEncryptInput(t *testing.T) {
+	key := useInputKey(t)
+
+	loaded, err := loadInputKey()
+	require.NoError(t, err)
+	assert.Equal(t, key, loaded)
+
+	data, err := encryptInput(key, []byte("1,2\n3,4"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "1,2")
+
+	plaintext, err := decryptInput(key, data)
+	require.NoError(t, err)
+	assert.Equal(t, "1,2\n3,4", string(plaintext))
+
+	again, err := encryptInput(key, []byte("1,2\n3,4"))
+	require.NoError(t, err)
+	assert.NotEqual(t, data, again, "each encryption should use a new nonce")
+
+	other := make([]byte, inputKeySize)
+	_, err = decryptInput(other, data)
+	assert.ErrorContains(t, err, "key is probably wrong")
+
+	data[len(data)-1] ^= 1
+	_, err = decryptInput(key, data)
+	assert.Error(t, err)
+
+	_, err = decryptInput(key, []byte("1,2\n3,4"))
+	assert.ErrorContains(t, err, "not an encrypted input")
+}
+
+func TestLoadInputKey(t *testing.T) {
+	t.Setenv("AOC_INPUT_KEY", "")
+	t.Setenv("AOC_INPUT_KEY_FILE", filepath.Join(t.TempDir(), "missing.key"))
+	_, err := loadInputKey()
+	assert.ErrorIs(t, err, ErrNoInputKey)
+
+	t.Setenv("AOC_INPUT_KEY", strings.Repeat("ab", inputKeySize))
+	key, err := loadInputKey()
+	require.NoError(t, err)
+	assert.Len(t, key, inputKeySize)
+
+	t.Setenv("AOC_INPUT_KEY", "abcd")
+	_, err = loadInputKey()
+	assert.ErrorContains(t, err, "invalid input key in AOC_INPUT_KEY")
+}
+
+func TestReadFileEncrypted(t *testing.T) {
+	key := useInputKey(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "input.txt")
+	require.NoError(t, os.WriteFile(path, []byte("1\n2\n3"), 0644))
+
+	encrypted, err := encryptDayInput(key, dir, false)
+	require.NoError(t, err)
+	assert.True(t, encrypted)
+	assert.NoFileExists(t, path)
+	assert.True(t, InputExists(path))
+
+	nums, err := ReadIntegerFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, nums)
+
+	t.Setenv("AOC_INPUT_KEY_FILE", filepath.Join(dir, "missing.key"))
+	_, err = ReadFile(path)
+	assert.ErrorIs(t, err, ErrNoInputKey)
+
+	_, err = ReadFile(filepath.Join(dir, "sample.txt"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	assert.False(t, InputExists(filepath.Join(dir, "sample.txt")))
+}
+
+func TestEncryptDayInputs(t *testing.T) {
+	key := useInputKey(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "input.txt")
+
+	encrypted, err := encryptDayInput(key, dir, false)
+	require.NoError(t, err)
+	assert.False(t, encrypted, "there's no input to encrypt")
+
+	require.NoError(t, os.WriteFile(path, []byte("hello"), 0644))
+	encrypted, err = encryptDayInput(key, dir, true)
+	require.NoError(t, err)
+	assert.True(t, encrypted)
+	assert.FileExists(t, path)
+	assert.FileExists(t, path+EncryptedSuffix)
+
+	decrypted, err := decryptDayInput(key, dir, false)
+	require.NoError(t, err)
+	assert.True(t, decrypted, "an identical input.txt can be overwritten")
+	assert.NoFileExists(t, path+EncryptedSuffix)
+
+	encrypted, err = encryptDayInput(key, dir, true)
+	require.NoError(t, err)
+	assert.True(t, encrypted)
+	require.NoError(t, os.WriteFile(path, []byte("edited"), 0644))
+	_, err = decryptDayInput(key, dir, false)
+	assert.ErrorContains(t, err, "different contents")
+
+	require.NoError(t, os.Remove(path))
+	decrypted, err = decryptDayInput(key, dir, true)
+	require.NoError(t, err)
+	assert.True(t, decrypted)
+	contents, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(contents))
+	assert.FileExists(t, path+EncryptedSuffix)
+}
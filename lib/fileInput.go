@@ -2,16 +2,82 @@ package lib
 
 import (
 	"errors"
+	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 )
 
+var (
+	inlineMu     sync.Mutex
+	inlineInputs = map[string]string{}
+	inlineCount  int
+)
+
+// inlinePrefix marks the names handed out by StringInput, so ReadFile knows to look them up first
+const inlinePrefix = "inline:"
+
+// StringInput makes contents readable by ReadFile (and so by any day's Init), returning the name
+// to pass in place of a path. Useful for stdin, or inline samples in tests. The contents are kept
+// until the name is passed to ReleaseInput.
+func StringInput(contents string) string {
+	inlineMu.Lock()
+	defer inlineMu.Unlock()
+
+	name := inlinePrefix + strconv.Itoa(inlineCount)
+	inlineCount++
+	inlineInputs[name] = contents
+	return name
+}
+
+// ReleaseInput forgets an input from StringInput once it's no longer needed. Names that aren't
+// from StringInput are ignored, so it's safe to call with any path.
+func ReleaseInput(name string) {
+	inlineMu.Lock()
+	defer inlineMu.Unlock()
+
+	delete(inlineInputs, name)
+}
+
+// ReaderInput reads r to the end and returns a name for the contents, like StringInput
+func ReaderInput(r io.Reader) (string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	return StringInput(string(data)), nil
+}
+
 /// ReadStringFile reads a file and parses it as an array of strings
 func ReadFile(relativePath string) (string, error) {
-	pwd, _ := os.Getwd()
-	data, err := os.ReadFile(filepath.Join(pwd, relativePath))
+	if strings.HasPrefix(relativePath, inlinePrefix) {
+		inlineMu.Lock()
+		contents, ok := inlineInputs[relativePath]
+		inlineMu.Unlock()
+
+		// otherwise it's a file that just happens to have a name like one
+		if ok {
+			return contents, nil
+		}
+	}
+
+	path := relativePath
+	if !filepath.IsAbs(path) {
+		pwd, _ := os.Getwd()
+		path = filepath.Join(pwd, relativePath)
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		// fall back to an encrypted copy, keeping the original error if there isn't one either
+		contents, encryptedErr := readEncrypted(path)
+		if !errors.Is(encryptedErr, fs.ErrNotExist) {
+			return contents, encryptedErr
+		}
+	}
 	if err != nil {
 		return "", err
 	}
@@ -0,0 +1,51 @@
+package lib
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStringInput(t *testing.T) {
+	name := StringInput("1\n2\n3")
+
+	nums, err := ReadIntegerFile(name)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, nums)
+
+	name, err = ReaderInput(strings.NewReader("a,b"))
+	require.NoError(t, err)
+
+	rows, err := ReadDelimitedFile(name, ",")
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"a", "b"}}, rows)
+
+	_, err = ReadFile(inlinePrefix + "missing")
+	assert.Error(t, err)
+
+	ReleaseInput(name)
+	_, err = ReadFile(name)
+	assert.Error(t, err, "a released input can't be read any more")
+}
+
+func TestReadFileInlineLookalike(t *testing.T) {
+	t.Chdir(t.TempDir())
+	require.NoError(t, os.WriteFile(inlinePrefix+"notes.txt", []byte("on disk"), 0644))
+
+	contents, err := ReadFile(inlinePrefix + "notes.txt")
+	require.NoError(t, err)
+	assert.Equal(t, "on disk", contents)
+}
+
+func TestReadFileAbsolutePath(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "input.txt")
+	require.NoError(t, os.WriteFile(path, []byte("hello"), 0644))
+
+	contents, err := ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", contents)
+}
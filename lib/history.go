@@ -0,0 +1,360 @@
+package lib
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+// HistoryFile is the name of the file in each day's directory that every run is appended to
+const HistoryFile = "history.jsonl"
+
+// HistoryEntry is one part's result from a past run
+type HistoryEntry struct {
+	Time  time.Time `json:"time"`
+	Day   int       `json:"day"`
+	Part  string    `json:"part"`
+	Input string    `json:"input"`
+	// InputHash identifies the contents of the input, so renamed or edited inputs are told apart
+	InputHash string `json:"input_hash"`
+	// Commit is the git commit the run was built from, with "-dirty" if there were local changes
+	Commit     string `json:"commit,omitempty"`
+	Solver     string `json:"solver,omitempty"`
+	Answer     string `json:"answer"`
+	DurationNs int64  `json:"duration_ns"`
+}
+
+func (e HistoryEntry) Duration() time.Duration {
+	return time.Duration(e.DurationNs)
+}
+
+// LoadHistory reads the run history from a day's directory, oldest first
+func LoadHistory(dir string) ([]HistoryEntry, error) {
+	f, err := os.Open(filepath.Join(dir, HistoryFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	history := []HistoryEntry{}
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var e HistoryEntry
+		err = json.Unmarshal(scanner.Bytes(), &e)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s line %d: %w", HistoryFile, line, err)
+		}
+		history = append(history, e)
+	}
+	return history, scanner.Err()
+}
+
+// appendHistory adds entries to the end of the history in a day's directory
+func appendHistory(dir string, entries []HistoryEntry) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, e := range entries {
+		err := enc.Encode(e)
+		if err != nil {
+			return err
+		}
+	}
+
+	f, err := os.OpenFile(filepath.Join(dir, HistoryFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	// a single write, so concurrent runs don't interleave lines
+	_, err = f.Write(buf.Bytes())
+	if err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// inputHash returns a short hash of an input's contents
+func inputHash(path string) (string, error) {
+	contents, err := ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(contents))
+	return hex.EncodeToString(sum[:])[:12], nil
+}
+
+// gitCommit is the commit of the working tree the process runs in, or empty outside of git
+var gitCommit = sync.OnceValue(func() string {
+	commit, err := exec.Command("git", "rev-parse", "--short", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	status, err := exec.Command("git", "status", "--porcelain", "--untracked-files=no").Output()
+	if err == nil && len(bytes.TrimSpace(status)) > 0 {
+		return strings.TrimSpace(string(commit)) + "-dirty"
+	}
+	return strings.TrimSpace(string(commit))
+})
+
+// recordHistory appends the parts that produced an answer to the history in dir. A warning is
+// logged for each answer that differs from the last one recorded for the same input contents.
+func recordHistory(dir string, in Input, results []Result) error {
+	hash, err := inputHash(in.Path)
+	if err != nil {
+		// the run already reported that the input couldn't be read
+		return nil
+	}
+	history, err := LoadHistory(dir)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	entries := []HistoryEntry{}
+	for _, r := range results {
+		if r.Part == "init" || r.Failed() || r.Skipped {
+			continue
+		}
+
+		e := HistoryEntry{
+			Time:       now,
+			Day:        r.Day,
+			Part:       r.Part,
+			Input:      in.Name,
+			InputHash:  hash,
+			Commit:     gitCommit(),
+			Solver:     r.Solver,
+			Answer:     r.Answer,
+			DurationNs: r.DurationNs,
+		}
+		if previous, ok := lastAnswer(history, e); ok && previous.Answer != e.Answer {
+			Log.Warn("answer changed for the same input", "day", e.Day, "part", e.Part, "input", e.Input,
+				"previous", previous.Answer, "previous_commit", previous.Commit, "answer", e.Answer)
+		}
+		entries = append(entries, e)
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+	return appendHistory(dir, entries)
+}
+
+// lastAnswer finds the most recent entry for the same part of the same input
+func lastAnswer(history []HistoryEntry, e HistoryEntry) (HistoryEntry, bool) {
+	for _, previous := range slices.Backward(history) {
+		if previous.Day == e.Day && previous.Part == e.Part && previous.InputHash == e.InputHash {
+			return previous, true
+		}
+	}
+	return HistoryEntry{}, false
+}
+
+// recordRun saves a run's results to the history of the day in dir, grouped by input. It only
+// logs failures, since the history shouldn't get in the way of the answers.
+func recordRun(dir string, inputs []Input, results []Result) {
+	for _, in := range inputs {
+		forInput := []Result{}
+		for _, r := range results {
+			if r.Input == in.Name {
+				forInput = append(forInput, r)
+			}
+		}
+
+		err := recordHistory(dir, in, forInput)
+		if err != nil {
+			Log.Warn("failed to save the run history", "dir", dir, "err", err)
+		}
+	}
+}
+
+// shouldRecord reports whether a run belongs in the history. Runs with a profile being recorded or
+// several days running at once are slowed down by things other than the code, so their timings
+// would show up as regressions.
+func shouldRecord(c *cli.Context, opts runOptions, concurrent bool) bool {
+	if c.Bool("no-history") {
+		return false
+	}
+	if opts.profile.enabled() || concurrent {
+		Log.Info("not saving the run history, since timings from runs with profiles or --jobs aren't comparable")
+		return false
+	}
+	return true
+}
+
+// historyTrend is the runs of one part of one input with one solver
+type historyTrend struct {
+	Day       int
+	Part      string
+	Input     string
+	InputHash string
+	Solver    string
+	Runs      []HistoryEntry
+}
+
+// trendRuns is how many of the latest runs the trend shows, and the baseline a run is compared to
+const trendRuns = 10
+
+// baseline is the median duration of the runs before the latest one, out of the last trendRuns
+func (t historyTrend) baseline() (time.Duration, bool) {
+	earlier := t.Runs[max(len(t.Runs)-trendRuns, 0) : len(t.Runs)-1]
+	if len(earlier) == 0 {
+		return 0, false
+	}
+
+	durations := make([]time.Duration, len(earlier))
+	for i, e := range earlier {
+		durations[i] = e.Duration()
+	}
+	slices.Sort(durations)
+	return durations[len(durations)/2], true
+}
+
+// change is how much slower (or faster, if negative) the latest run was than the baseline, in percent
+func (t historyTrend) change() (float64, bool) {
+	baseline, ok := t.baseline()
+	if !ok || baseline == 0 {
+		return 0, false
+	}
+	latest := t.Runs[len(t.Runs)-1].Duration()
+	return 100 * float64(latest-baseline) / float64(baseline), true
+}
+
+// answers lists the different answers the runs produced, in the order they first appeared
+func (t historyTrend) answers() []string {
+	answers := []string{}
+	for _, e := range t.Runs {
+		if !slices.Contains(answers, e.Answer) {
+			answers = append(answers, e.Answer)
+		}
+	}
+	return answers
+}
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline draws the durations of the latest runs, scaled between the fastest and slowest of them
+func (t historyTrend) sparkline() string {
+	runs := t.Runs[max(len(t.Runs)-trendRuns, 0):]
+	lowest, highest := runs[0].DurationNs, runs[0].DurationNs
+	for _, e := range runs {
+		lowest = min(lowest, e.DurationNs)
+		highest = max(highest, e.DurationNs)
+	}
+
+	var line strings.Builder
+	for _, e := range runs {
+		i := 0
+		if highest > lowest {
+			i = int((e.DurationNs - lowest) * int64(len(sparkBlocks)-1) / (highest - lowest))
+		}
+		line.WriteRune(sparkBlocks[i])
+	}
+	return line.String()
+}
+
+// historyTrends groups a day's history into trends, in the order they were first run
+func historyTrends(history []HistoryEntry) []*historyTrend {
+	trends := []*historyTrend{}
+	byKey := map[string]*historyTrend{}
+	for _, e := range history {
+		key := fmt.Sprintf("%d/%s/%s/%s", e.Day, e.Part, e.InputHash, e.Solver)
+		t, ok := byKey[key]
+		if !ok {
+			t = &historyTrend{Day: e.Day, Part: e.Part, InputHash: e.InputHash, Solver: e.Solver}
+			byKey[key] = t
+			trends = append(trends, t)
+		}
+		t.Input = e.Input
+		t.Runs = append(t.Runs, e)
+	}
+	return trends
+}
+
+// showHistory prints the trends for each day's history and flags the parts whose latest run was
+// more than threshold percent slower than the runs before it. Returns the number of regressions.
+func showHistory(w io.Writer, days []int, dirs []string, threshold float64) (int, error) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "day\tpart\tinput\truns\tbest\tlatest\tchange\ttrend\tcommit\tnotes")
+
+	regressions := 0
+	for i, dir := range dirs {
+		history, err := LoadHistory(dir)
+		if err != nil {
+			return regressions, fmt.Errorf("day %d: %w", days[i], err)
+		}
+
+		for _, t := range historyTrends(history) {
+			latest := t.Runs[len(t.Runs)-1]
+			best := latest.Duration()
+			for _, e := range t.Runs {
+				best = min(best, e.Duration())
+			}
+
+			part := t.Part
+			if t.Solver != "" {
+				part += " (" + t.Solver + ")"
+			}
+			change := "-"
+			notes := []string{}
+			if percent, ok := t.change(); ok {
+				change = fmt.Sprintf("%+.0f%%", percent)
+				if percent > threshold {
+					notes = append(notes, "REGRESSION")
+					regressions++
+				}
+			}
+			if answers := t.answers(); len(answers) > 1 {
+				notes = append(notes, "answer changed: "+strings.Join(answers, " -> "))
+			}
+
+			fmt.Fprintf(tw, "%d\t%s\t%s (%s)\t%d\t%v\t%v\t%s\t%s\t%s\t%s\n",
+				t.Day, part, t.Input, t.InputHash[:min(len(t.InputHash), 6)], len(t.Runs),
+				best.Round(time.Microsecond), latest.Duration().Round(time.Microsecond),
+				change, t.sparkline(), latest.Commit, strings.Join(notes, ", "))
+		}
+	}
+	return regressions, tw.Flush()
+}
+
+// historyCommand shows the history of the days in dirs, failing if there were any regressions
+func historyCommand(c *cli.Context, days []int, dirs []string) error {
+	regressions, err := showHistory(os.Stdout, days, dirs, c.Float64("threshold"))
+	if err != nil {
+		return err
+	}
+	if regressions > 0 {
+		return cli.Exit(fmt.Sprintf("%d parts got more than %v%% slower", regressions, c.Float64("threshold")), 1)
+	}
+	return nil
+}
+
+// historyFlags are shared by the single and multi-day history commands
+var historyFlags = []cli.Flag{
+	&cli.Float64Flag{
+		Name:  "threshold",
+		Value: 20,
+		Usage: "flag parts whose latest run is more than `PERCENT` slower than the median of the runs before it",
+	},
+}
@@ -0,0 +1,82 @@
+package lib
+
+import (
+	"flag"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+)
+
+func TestRecordHistory(t *testing.T) {
+	dir := t.TempDir()
+	in := Input{Name: "sample", Path: StringInput("1 2 3")}
+
+	var logged strings.Builder
+	defer func(l *slog.Logger) { Log = l }(Log)
+	Log = slog.New(slog.NewTextHandler(&logged, nil))
+
+	require.NoError(t, recordHistory(dir, in, []Result{
+		{Day: 99, Part: "init", Input: "sample"},
+		{Day: 99, Part: "part1", Input: "sample", Answer: "6", DurationNs: 100},
+		{Day: 99, Part: "part2", Input: "sample", Skipped: true},
+	}))
+	require.NoError(t, recordHistory(dir, in, []Result{
+		{Day: 99, Part: "part1", Input: "sample", Answer: "7", DurationNs: 200},
+		{Day: 99, Part: "part2", Input: "sample", Error: "oops", ExitStatus: 1},
+	}))
+
+	history, err := LoadHistory(dir)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, "6", history[0].Answer)
+	assert.Equal(t, "7", history[1].Answer)
+	assert.Equal(t, history[0].InputHash, history[1].InputHash)
+	assert.Equal(t, 200*time.Nanosecond, history[1].Duration())
+
+	assert.Contains(t, logged.String(), `msg="answer changed for the same input" day=99 part=part1 input=sample previous=6`)
+}
+
+func TestShowHistory(t *testing.T) {
+	dir := t.TempDir()
+	entries := []HistoryEntry{}
+	for _, ms := range []int{10, 12, 11, 10, 15} {
+		entries = append(entries, HistoryEntry{Day: 99, Part: "part1", Input: "input", InputHash: "abcdef123456", Commit: "abc", Answer: "6", DurationNs: int64(ms) * 1e6})
+	}
+	entries = append(entries,
+		HistoryEntry{Day: 99, Part: "part2", Input: "input", InputHash: "abcdef123456", Answer: "1", DurationNs: 1e6},
+		HistoryEntry{Day: 99, Part: "part2", Input: "input", InputHash: "abcdef123456", Answer: "2", DurationNs: 1e6},
+	)
+	require.NoError(t, appendHistory(dir, entries))
+
+	var out strings.Builder
+	regressions, err := showHistory(&out, []int{99}, []string{dir}, 20)
+	require.NoError(t, err)
+	assert.Equal(t, 1, regressions)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.Regexp(t, `^99\s+part1\s+input \(abcdef\)\s+5\s+10ms\s+15ms\s+\+36%\s+▁▃▂▁█\s+abc\s+REGRESSION$`, lines[1])
+	assert.Regexp(t, `^99\s+part2\s+input \(abcdef\)\s+2\s+1ms\s+1ms\s+\+0%\s+▁▁\s+answer changed: 1 -> 2$`, lines[2])
+
+	regressions, err = showHistory(&out, []int{99}, []string{dir}, 50)
+	require.NoError(t, err)
+	assert.Equal(t, 0, regressions)
+}
+
+func TestShouldRecord(t *testing.T) {
+	set := flag.NewFlagSet("run", flag.ContinueOnError)
+	set.Bool("no-history", false, "")
+	c := cli.NewContext(nil, set, nil)
+
+	assert.True(t, shouldRecord(c, runOptions{}, false))
+	assert.False(t, shouldRecord(c, runOptions{}, true))
+	assert.False(t, shouldRecord(c, runOptions{profile: profileOptions{cpu: true}}, false))
+
+	require.NoError(t, set.Set("no-history", "true"))
+	assert.False(t, shouldRecord(c, runOptions{}, false))
+}
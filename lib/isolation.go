@@ -0,0 +1,164 @@
+package lib
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Cloner is implemented by days whose parts change the day's state. Each part runs on its own
+// clone, so neither part sees what the other did. Clone has to copy anything a part modifies,
+// like slices that are changed in place.
+type Cloner interface {
+	Day
+	Clone() Day
+}
+
+// partDay returns the day that a part should run on: a clone for days that implement Cloner, or
+// with isolate a freshly initialized instance for every part after the first
+func partDay(d Day, number int, in Input, isolate bool, first bool) (Day, error) {
+	if cloner, ok := d.(Cloner); ok {
+		return cloner.Clone(), nil
+	}
+	if !isolate || first {
+		return d, nil
+	}
+
+	fresh, err := NewDay(number)
+	if err != nil {
+		// days run on their own aren't always registered, so settle for initializing it again
+		fresh = d
+	}
+	_, err = callSafely(func() (string, error) {
+		return "", fresh.Init(in.Path)
+	})
+	return fresh, err
+}
+
+// maxStateChanges limits how many changes checkMutation lists
+const maxStateChanges = 10
+
+// daySnapshot flattens a day's state into the values at each path reachable from it, like
+// "Paper[3][4]", so it can be compared after a part runs
+type daySnapshot map[string]string
+
+func snapshotState(d Day) daySnapshot {
+	s := daySnapshot{}
+	s.add("", reflect.ValueOf(d), map[uintptr]bool{})
+	return s
+}
+
+func (s daySnapshot) add(path string, v reflect.Value, seen map[uintptr]bool) {
+	switch v.Kind() {
+	case reflect.Invalid:
+		s[path] = "nil"
+	case reflect.Pointer:
+		if v.IsNil() {
+			s[path] = "nil"
+			return
+		}
+		// a pointer that was already visited is a cycle or shared data, which is covered at its first path
+		if seen[v.Pointer()] {
+			return
+		}
+		seen[v.Pointer()] = true
+		s.add(path, v.Elem(), seen)
+	case reflect.Interface:
+		if v.IsNil() {
+			s[path] = "nil"
+			return
+		}
+		s.add(path, v.Elem(), seen)
+	case reflect.Struct:
+		for i := range v.NumField() {
+			s.add(strings.TrimPrefix(path+"."+v.Type().Field(i).Name, "."), v.Field(i), seen)
+		}
+	case reflect.Slice, reflect.Array:
+		s[path+".len"] = fmt.Sprint(v.Len())
+		for i := range v.Len() {
+			s.add(fmt.Sprintf("%s[%d]", path, i), v.Index(i), seen)
+		}
+	case reflect.Map:
+		s[path+".len"] = fmt.Sprint(v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			s.add(fmt.Sprintf("%s[%s]", path, formatValue(iter.Key())), iter.Value(), seen)
+		}
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		// not state that a part can change
+	default:
+		s[path] = formatValue(v)
+	}
+}
+
+// formatValue prints a basic value, which works for unexported fields too (unlike Interface)
+func formatValue(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.Bool:
+		return fmt.Sprint(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return fmt.Sprint(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return fmt.Sprint(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return fmt.Sprint(v.Float())
+	case reflect.Complex64, reflect.Complex128:
+		return fmt.Sprint(v.Complex())
+	case reflect.String:
+		return fmt.Sprintf("%q", v.String())
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return "nil"
+		}
+		return formatValue(v.Elem())
+	case reflect.Struct, reflect.Array:
+		fields := make([]string, 0, v.NumField())
+		if v.Kind() == reflect.Array {
+			for i := range v.Len() {
+				fields = append(fields, formatValue(v.Index(i)))
+			}
+		} else {
+			for i := range v.NumField() {
+				fields = append(fields, formatValue(v.Field(i)))
+			}
+		}
+		return "{" + strings.Join(fields, " ") + "}"
+	default:
+		return v.Kind().String()
+	}
+}
+
+// changes lists the paths that differ between two snapshots, like "Paper[3][4]: true -> false"
+func (s daySnapshot) changes(after daySnapshot) []string {
+	changes := []string{}
+	for path, before := range s {
+		if now, ok := after[path]; !ok {
+			changes = append(changes, fmt.Sprintf("%s: %s -> removed", path, before))
+		} else if now != before {
+			changes = append(changes, fmt.Sprintf("%s: %s -> %s", path, before, now))
+		}
+	}
+	for path, now := range after {
+		if _, ok := s[path]; !ok {
+			changes = append(changes, fmt.Sprintf("%s: added %s", path, now))
+		}
+	}
+	sort.Strings(changes)
+	return changes
+}
+
+// checkMutation logs a warning listing how a part changed the day's state since before was taken
+func checkMutation(ctx context.Context, before daySnapshot, d Day, number int, part string) {
+	changes := before.changes(snapshotState(d))
+	if len(changes) == 0 {
+		return
+	}
+
+	listed := changes[:min(len(changes), maxStateChanges)]
+	if len(changes) > len(listed) {
+		listed = append(listed, fmt.Sprintf("and %d more", len(changes)-len(listed)))
+	}
+	Log.WarnContext(ctx, "part changed the day's state", "day", number, "part", part, "changes", strings.Join(listed, "; "))
+}
@@ -0,0 +1,111 @@
+package lib
+
+import (
+	"context"
+	"log/slog"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingDay's part 1 uses up its input, so part 2 only gets the right answer on untouched state
+type countingDay struct {
+	Values []int
+	sum    int
+}
+
+func (d *countingDay) Init(input string) error {
+	d.Values = []int{1, 2, 3}
+	d.sum = 0
+	return nil
+}
+
+func (d *countingDay) Part1() (string, error) {
+	for len(d.Values) > 0 {
+		d.sum += d.Values[0]
+		d.Values = d.Values[1:]
+	}
+	return strconv.Itoa(d.sum), nil
+}
+
+func (d *countingDay) Part2() (string, error) {
+	return strconv.Itoa(len(d.Values)), nil
+}
+
+// cloningDay is a countingDay that copies its state for each part
+type cloningDay struct {
+	countingDay
+}
+
+func (d *cloningDay) Clone() Day {
+	return &cloningDay{countingDay{Values: append([]int{}, d.Values...)}}
+}
+
+func answers(results []Result) []string {
+	answers := []string{}
+	for _, r := range results[1:] {
+		answers = append(answers, r.Answer)
+	}
+	return answers
+}
+
+func TestIsolation(t *testing.T) {
+	in := Input{Name: "input"}
+
+	results := runParts(context.Background(), &countingDay{}, 99, in, "all", runOptions{}, nopReporter{})
+	assert.Equal(t, []string{"6", "0"}, answers(results))
+
+	results = runParts(context.Background(), &countingDay{}, 99, in, "all", runOptions{isolate: true}, nopReporter{})
+	assert.Equal(t, []string{"6", "3"}, answers(results))
+
+	results = runParts(context.Background(), &cloningDay{}, 99, in, "all", runOptions{}, nopReporter{})
+	assert.Equal(t, []string{"6", "3"}, answers(results))
+}
+
+func TestCheckMutation(t *testing.T) {
+	var logged strings.Builder
+	defer func(l *slog.Logger) { Log = l }(Log)
+	Log = slog.New(slog.NewTextHandler(&logged, nil))
+
+	runParts(context.Background(), &countingDay{}, 99, Input{Name: "input"}, "all", runOptions{checkMutation: true}, nopReporter{})
+	assert.Contains(t, logged.String(), `msg="part changed the day's state" day=99 part=part1 changes="Values.len: 3 -> 0; Values[0]: 1 -> removed;`)
+	assert.NotContains(t, logged.String(), "part=part2")
+}
+
+func TestSnapshotChanges(t *testing.T) {
+	d := &countingDay{}
+	require.NoError(t, d.Init(""))
+	before := snapshotState(d)
+	assert.Empty(t, before.changes(snapshotState(d)))
+
+	d.Values[1] = 5
+	d.Values = d.Values[1:]
+	d.sum = 1
+	assert.Equal(t, []string{
+		"Values.len: 3 -> 2",
+		"Values[0]: 1 -> 5",
+		"Values[1]: 2 -> 3",
+		"Values[2]: 3 -> removed",
+		"sum: 0 -> 1",
+	}, before.changes(snapshotState(d)))
+}
+
+func TestSnapshotCycles(t *testing.T) {
+	type node struct {
+		Next  *node
+		Names map[string]int
+	}
+	n := &node{Names: map[string]int{"a": 1}}
+	n.Next = n
+
+	s := snapshotState(&countingDay{})
+	assert.Equal(t, daySnapshot{"Values.len": "0", "sum": "0"}, s)
+
+	s = daySnapshot{}
+	s.add("", reflect.ValueOf(n), map[uintptr]bool{})
+	assert.Equal(t, daySnapshot{"Names.len": "1", `Names["a"]`: "1"}, s)
+}
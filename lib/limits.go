@@ -0,0 +1,160 @@
+package lib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"runtime/debug"
+	"runtime/metrics"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	// ErrMemoryLimit is the cause of a day being stopped by --mem-limit
+	ErrMemoryLimit = errors.New("over the memory limit")
+	// ErrTimeLimit is the cause of a day being stopped by --time-limit
+	ErrTimeLimit = errors.New("over the time limit")
+)
+
+// watchdogInterval is how often the watchdog checks the heap. Days can allocate quickly, so it's
+// frequent, and reading the heap metric doesn't stop the world like runtime.ReadMemStats.
+const watchdogInterval = 10 * time.Millisecond
+
+// heapMetric is the memory taken up by heap objects, live or not yet swept
+const heapMetric = "/memory/classes/heap/objects:bytes"
+
+// limitOptions cap the resources a whole day (Init and both parts) can use
+type limitOptions struct {
+	// memory is the most heap the day can use in bytes, if non-zero
+	memory int64
+	// time is how long the day can take, if non-zero
+	time time.Duration
+}
+
+func (l limitOptions) enabled() bool {
+	return l.memory > 0 || l.time > 0
+}
+
+// watchdog stops a day that goes over its limits, and tracks the peak heap usage
+type watchdog struct {
+	limits limitOptions
+	cancel context.CancelCauseFunc
+	done   chan struct{}
+	peak   atomic.Uint64
+	// previousLimit is the runtime's memory limit before the day started
+	previousLimit int64
+}
+
+// start returns a context that's cancelled once the day goes over a limit, and the watchdog that
+// enforces it. The runtime's memory limit is set too, so the GC works harder as the day nears it.
+// The watchdog has to be stopped once the day is done.
+func (l limitOptions) start(ctx context.Context) (context.Context, *watchdog) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	w := &watchdog{limits: l, cancel: cancel, done: make(chan struct{})}
+	if l.time > 0 {
+		var cancelTimeout context.CancelFunc
+		ctx, cancelTimeout = context.WithTimeoutCause(ctx, l.time, fmt.Errorf("%w of %v", ErrTimeLimit, l.time))
+		w.cancel = func(cause error) {
+			cancel(cause)
+			cancelTimeout()
+		}
+	}
+	if l.memory > 0 {
+		w.previousLimit = debug.SetMemoryLimit(l.memory)
+	}
+
+	w.peak.Store(heapInUse())
+	go w.watch(ctx)
+	return ctx, w
+}
+
+func heapInUse() uint64 {
+	sample := []metrics.Sample{{Name: heapMetric}}
+	metrics.Read(sample)
+	return sample[0].Value.Uint64()
+}
+
+func (w *watchdog) watch(ctx context.Context) {
+	ticker := time.NewTicker(watchdogInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		heap := heapInUse()
+		peak := w.peak.Load()
+		for heap > peak && !w.peak.CompareAndSwap(peak, heap) {
+			peak = w.peak.Load()
+		}
+		if w.limits.memory > 0 && heap > uint64(w.limits.memory) {
+			w.cancel(fmt.Errorf("%w of %s, the heap reached %s", ErrMemoryLimit, formatBytes(uint64(w.limits.memory)), formatBytes(heap)))
+			return
+		}
+	}
+}
+
+// takePeak returns the peak heap usage since the last call, so each phase gets its own
+func (w *watchdog) takePeak() uint64 {
+	return w.peak.Swap(heapInUse())
+}
+
+// stop ends the watchdog and puts the runtime's memory limit back
+func (w *watchdog) stop() {
+	close(w.done)
+	w.cancel(nil)
+	if w.limits.memory > 0 {
+		debug.SetMemoryLimit(w.previousLimit)
+	}
+}
+
+var byteUnits = []string{"B", "KiB", "MiB", "GiB", "TiB"}
+
+// parseBytes reads a size like "512MiB" or "2GiB", in the same format as GOMEMLIMIT
+func parseBytes(s string) (int64, error) {
+	for i := len(byteUnits) - 1; i >= 0; i-- {
+		number, ok := strings.CutSuffix(s, byteUnits[i])
+		if !ok {
+			continue
+		}
+
+		value, err := strconv.ParseFloat(strings.TrimSpace(number), 64)
+		if err != nil || value < 0 {
+			break
+		}
+		bytes := value * math.Pow(1024, float64(i))
+		if bytes > math.MaxInt64 {
+			break
+		}
+		return int64(bytes), nil
+	}
+
+	value, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid size %q, use something like 512MiB or 2GiB", s)
+	}
+	return value, nil
+}
+
+// formatBytes prints a size with the largest unit that keeps it over 1, like 1.5GiB
+func formatBytes(b uint64) string {
+	value := float64(b)
+	i := 0
+	for value >= 1024 && i < len(byteUnits)-1 {
+		value /= 1024
+		i++
+	}
+	if i == 0 {
+		return fmt.Sprintf("%dB", b)
+	}
+	return strconv.FormatFloat(value, 'f', 1, 64) + byteUnits[i]
+}
@@ -0,0 +1,90 @@
+package lib
+
+import (
+	"context"
+	"runtime/debug"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hogDay's part 2 keeps allocating until it's cancelled
+type hogDay struct {
+	slowDay
+}
+
+func (d *hogDay) Part2Context(ctx context.Context) (string, error) {
+	hoard := [][]byte{}
+	for ctx.Err() == nil {
+		hoard = append(hoard, make([]byte, 1<<20))
+		time.Sleep(time.Millisecond)
+	}
+	return "", ctx.Err()
+}
+
+func TestParseBytes(t *testing.T) {
+	for s, expected := range map[string]int64{
+		"1024":   1024,
+		"512B":   512,
+		"1KiB":   1024,
+		"512MiB": 512 << 20,
+		"1.5GiB": 3 << 29,
+		"2 GiB":  2 << 30,
+		"1TiB":   1 << 40,
+		"0.5KiB": 512,
+	} {
+		actual, err := parseBytes(s)
+		assert.NoError(t, err, s)
+		assert.Equal(t, expected, actual, s)
+	}
+
+	for _, s := range []string{"", "lots", "-1", "-1MiB", "1MB", "1e30TiB", "100MiB "} {
+		_, err := parseBytes(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	assert.Equal(t, "512B", formatBytes(512))
+	assert.Equal(t, "1.0KiB", formatBytes(1024))
+	assert.Equal(t, "1.5GiB", formatBytes(3<<29))
+	assert.Equal(t, "2048.0TiB", formatBytes(2<<50))
+}
+
+func TestMemoryLimit(t *testing.T) {
+	previous := debug.SetMemoryLimit(-1)
+
+	opts := runOptions{limits: limitOptions{memory: 64 << 20}}
+	results := runParts(context.Background(), &hogDay{}, 99, Input{Name: "input"}, "all", opts, nopReporter{})
+	require.Len(t, results, 3)
+
+	assert.False(t, results[1].Failed())
+	assert.Equal(t, "Hello", results[1].Answer)
+
+	assert.Equal(t, ExitMemoryLimit, results[2].ExitStatus)
+	assert.Contains(t, results[2].Error, "over the memory limit of 64.0MiB")
+	assert.Greater(t, results[2].PeakHeapBytes, uint64(64<<20))
+	assert.Equal(t, ExitMemoryLimit, runExitStatus(results))
+
+	assert.Equal(t, previous, debug.SetMemoryLimit(-1), "the memory limit should be put back")
+}
+
+func TestTimeLimit(t *testing.T) {
+	d := &slowDay{stopped: make(chan struct{})}
+	opts := runOptions{limits: limitOptions{time: 20 * time.Millisecond}}
+	results := runParts(context.Background(), d, 99, Input{Name: "input"}, "all", opts, nopReporter{})
+	require.Len(t, results, 3)
+
+	assert.Equal(t, "Hello", results[1].Answer)
+	assert.NotZero(t, results[1].PeakHeapBytes)
+	assert.Equal(t, ExitTimeout, results[2].ExitStatus)
+	assert.Contains(t, results[2].Error, "over the time limit of 20ms")
+
+	select {
+	case <-d.stopped:
+	case <-time.After(time.Second):
+		t.Fatal("part 2 was not cancelled")
+	}
+}
@@ -0,0 +1,126 @@
+package lib
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+
+	"github.com/urfave/cli/v2"
+)
+
+var logLevel = new(slog.LevelVar)
+
+// Log is the logger days should use for any output other than their answers. It writes to stderr
+// so it doesn't get mixed up with the results. By default only warnings and errors are shown;
+// the runner's -v flag adds info and -vv adds debug, while --quiet only shows errors.
+//
+// When days run concurrently, each has its own output so their messages aren't interleaved. To
+// have a message go to the day's output, log it with the part's context, like Log.InfoContext(ctx,
+// ...). Anything else goes straight to stderr.
+var Log = slog.New(contextHandler{})
+
+// newLogHandler formats log messages written to w
+func newLogHandler(w io.Writer) slog.Handler {
+	return slog.NewTextHandler(w, &slog.HandlerOptions{
+		Level: logLevel,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			// timings are already reported by the runner
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	})
+}
+
+var stderrLogHandler = newLogHandler(os.Stderr)
+
+// contextHandler writes to the output attached to the context the message was logged with, or
+// stderr if there isn't one
+type contextHandler struct {
+	// with replays the attributes and groups added by With and WithGroup
+	with []func(slog.Handler) slog.Handler
+}
+
+func (h contextHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= logLevel.Level()
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	handler := stderrLogHandler
+	if w, ok := outputFromContext(ctx); ok {
+		handler = newLogHandler(w)
+	}
+	for _, with := range h.with {
+		handler = with(handler)
+	}
+	return handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{with: append(slices.Clip(h.with), func(handler slog.Handler) slog.Handler {
+		return handler.WithAttrs(attrs)
+	})}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{with: append(slices.Clip(h.with), func(handler slog.Handler) slog.Handler {
+		return handler.WithGroup(name)
+	})}
+}
+
+type outputKey struct{}
+
+// withOutput sends the logs and progress of the parts run with ctx to w instead of stderr. Writes
+// to w must be safe to make from multiple goroutines.
+func withOutput(ctx context.Context, w io.Writer) context.Context {
+	return context.WithValue(ctx, outputKey{}, w)
+}
+
+func outputFromContext(ctx context.Context) (io.Writer, bool) {
+	w, ok := ctx.Value(outputKey{}).(io.Writer)
+	return w, ok
+}
+
+func init() {
+	logLevel.Set(slog.LevelWarn)
+}
+
+// SetLogLevel changes the level of messages written by Log
+func SetLogLevel(level slog.Level) {
+	logLevel.Set(level)
+}
+
+var verbosity int
+
+// logFlags are added to every app built by the runner
+var logFlags = []cli.Flag{
+	&cli.BoolFlag{
+		Name:    "verbose",
+		Aliases: []string{"v"},
+		Usage:   "show info logs, or debug logs with -vv",
+		Count:   &verbosity,
+	},
+	&cli.BoolFlag{
+		Name:    "quiet",
+		Aliases: []string{"q"},
+		Usage:   "only show error logs",
+	},
+}
+
+// configureLogging sets the log level from the logFlags
+func configureLogging(c *cli.Context) error {
+	switch {
+	case c.Bool("quiet"):
+		SetLogLevel(slog.LevelError)
+	case verbosity >= 2:
+		SetLogLevel(slog.LevelDebug)
+	case verbosity == 1:
+		SetLogLevel(slog.LevelInfo)
+	default:
+		SetLogLevel(slog.LevelWarn)
+	}
+	return nil
+}
@@ -0,0 +1,277 @@
+package lib
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+// Result is the outcome of one phase ("init", "part1" or "part2") of running a day
+type Result struct {
+	Day    int    `json:"day"`
+	Part   string `json:"part"`
+	Input  string `json:"input"`
+	Answer string `json:"answer,omitempty"`
+	Error  string `json:"error,omitempty"`
+	// Solver is the variant that ran the part, when one was chosen with --solver
+	Solver string `json:"solver,omitempty"`
+	// Skipped is set for a part the puzzle doesn't have (see ErrNoPart)
+	Skipped bool `json:"skipped,omitempty"`
+	// Stack is where the day panicked, if it did
+	Stack      string `json:"stack,omitempty"`
+	DurationNs int64  `json:"duration_ns"`
+	ExitStatus int    `json:"exit_status"`
+	// PeakHeapBytes is the most heap in use during the phase, only measured with --mem-limit or --time-limit
+	PeakHeapBytes uint64 `json:"peak_heap_bytes,omitempty"`
+}
+
+func (r Result) Duration() time.Duration {
+	return time.Duration(r.DurationNs)
+}
+
+func (r Result) Failed() bool {
+	return r.ExitStatus != 0
+}
+
+// reporter writes results as each phase completes
+type reporter interface {
+	Report(r Result)
+	Close() error
+}
+
+func newReporter(format string, w io.Writer) (reporter, error) {
+	switch format {
+	case "", "text":
+		return &textReporter{w: w, errw: os.Stderr}, nil
+	case "json":
+		return &jsonReporter{w: w, results: []Result{}}, nil
+	case "ndjson":
+		return &ndjsonReporter{enc: json.NewEncoder(w)}, nil
+	case "table":
+		return &tableReporter{w: w}, nil
+	case "csv":
+		return newCSVReporter(w), nil
+	default:
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+}
+
+// textReporter prints the human readable output, with errors and stack traces going to errw
+type textReporter struct {
+	w    io.Writer
+	errw io.Writer
+	// days adds a heading before each day's results, for runs of more than one day
+	days    bool
+	lastDay int
+}
+
+func (t *textReporter) Report(r Result) {
+	if t.days && r.Day != t.lastDay {
+		if t.lastDay != 0 {
+			fmt.Fprintln(t.w)
+		}
+		fmt.Fprintf(t.w, "###### Day %d\n", r.Day)
+		t.lastDay = r.Day
+	}
+
+	if r.Part == "init" {
+		fmt.Fprintln(t.w, "======")
+		fmt.Fprintf(t.w, "Initialized in %dms\n", r.Duration().Milliseconds())
+		t.reportPeak(r)
+		if r.Failed() {
+			t.reportError(r)
+		}
+		return
+	}
+
+	fmt.Fprintln(t.w)
+	fmt.Fprintln(t.w, "======")
+	if r.Solver != "" {
+		fmt.Fprintf(t.w, "Part %s completed in %dms with the %s solver\n", r.Part[4:], r.Duration().Milliseconds(), r.Solver)
+	} else {
+		fmt.Fprintf(t.w, "Part %s completed in %dms\n", r.Part[4:], r.Duration().Milliseconds())
+	}
+
+	t.reportPeak(r)
+
+	if r.Failed() {
+		t.reportError(r)
+	} else if r.Skipped {
+		fmt.Fprintln(t.w, "No such part")
+	} else {
+		fmt.Fprintf(t.w, "Result: %s\n", r.Answer)
+	}
+}
+
+func (t *textReporter) reportPeak(r Result) {
+	if r.PeakHeapBytes > 0 {
+		fmt.Fprintf(t.w, "Peak heap %s\n", formatBytes(r.PeakHeapBytes))
+	}
+}
+
+func (t *textReporter) reportError(r Result) {
+	fmt.Fprintf(t.errw, "Error:\n%v\n", r.Error)
+	if r.Stack != "" {
+		fmt.Fprintf(t.errw, "\n%s", r.Stack)
+	}
+}
+
+func (t *textReporter) Close() error {
+	return nil
+}
+
+// jsonReporter writes all of the results as a single JSON array once the run is complete
+type jsonReporter struct {
+	w       io.Writer
+	results []Result
+}
+
+func (j *jsonReporter) Report(r Result) {
+	j.results = append(j.results, r)
+}
+
+func (j *jsonReporter) Close() error {
+	enc := json.NewEncoder(j.w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(j.results)
+}
+
+// ndjsonReporter writes each result as a line of JSON as soon as it's available
+type ndjsonReporter struct {
+	enc *json.Encoder
+}
+
+func (n *ndjsonReporter) Report(r Result) {
+	n.enc.Encode(r)
+}
+
+func (n *ndjsonReporter) Close() error {
+	return nil
+}
+
+// batchRow reports whether a result gets a row in the table and CSV formats. Successful Inits are
+// left out since they don't have an answer.
+func batchRow(r Result) bool {
+	return r.Part != "init" || r.Failed()
+}
+
+// tableReporter prints the results as an aligned table once the run is complete
+type tableReporter struct {
+	w       io.Writer
+	results []Result
+}
+
+func (t *tableReporter) Report(r Result) {
+	if batchRow(r) {
+		t.results = append(t.results, r)
+	}
+}
+
+func (t *tableReporter) Close() error {
+	// the day is only shown when there's more than one
+	days := false
+	for _, r := range t.results {
+		days = days || r.Day != t.results[0].Day
+	}
+
+	w := tabwriter.NewWriter(t.w, 0, 0, 2, ' ', 0)
+	if days {
+		fmt.Fprint(w, "day\t")
+	}
+	fmt.Fprintln(w, "input\tpart\tanswer\ttime")
+	for _, r := range t.results {
+		answer := r.Answer
+		if r.Failed() {
+			answer = "ERROR: " + r.Error
+		} else if r.Skipped {
+			answer = "-"
+		}
+		if days {
+			fmt.Fprintf(w, "%d\t", r.Day)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%v\n", r.Input, r.Part, answer, r.Duration().Round(time.Microsecond))
+	}
+	return w.Flush()
+}
+
+// csvReporter writes a CSV row for each result as it completes
+type csvReporter struct {
+	w *csv.Writer
+}
+
+func newCSVReporter(w io.Writer) *csvReporter {
+	c := &csvReporter{w: csv.NewWriter(w)}
+	c.w.Write([]string{"day", "input", "part", "answer", "error", "duration_ns"})
+	return c
+}
+
+func (c *csvReporter) Report(r Result) {
+	if !batchRow(r) {
+		return
+	}
+	c.w.Write([]string{strconv.Itoa(r.Day), r.Input, r.Part, r.Answer, r.Error, strconv.FormatInt(r.DurationNs, 10)})
+	c.w.Flush()
+}
+
+func (c *csvReporter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// nopReporter discards results, for commands that do their own printing
+type nopReporter struct{}
+
+func (nopReporter) Report(r Result) {}
+func (nopReporter) Close() error    { return nil }
+
+// runFlags are shared by every command that runs a day's parts
+var runFlags = append([]cli.Flag{
+	&cli.StringFlag{
+		Name:  "format",
+		Value: "text",
+		Usage: "output format: text, json, ndjson, table or csv",
+	},
+	&cli.StringFlag{
+		Name:  "input",
+		Usage: "read the input from `PATH` instead of the day's directory, or - for stdin",
+	},
+	&cli.StringFlag{
+		Name:  "inputs",
+		Usage: "run once for each file matching `GLOB`, e.g. 'inputs/*.txt'",
+	},
+	&cli.DurationFlag{
+		Name:  "timeout",
+		Usage: "stop each part after this long, e.g. 30s",
+	},
+	&cli.StringFlag{
+		Name:  "solver",
+		Usage: "run each part with the solver called `NAME`, for days that have more than one",
+	},
+	&cli.StringFlag{
+		Name:  "mem-limit",
+		Usage: "stop a day once its heap goes over `SIZE`, e.g. 512MiB or 2GiB",
+	},
+	&cli.DurationFlag{
+		Name:  "time-limit",
+		Usage: "stop a day once Init and its parts have taken this long in total, e.g. 1m",
+	},
+	&cli.BoolFlag{
+		Name:  "no-history",
+		Usage: "don't add the run to the day's " + HistoryFile,
+	},
+	&cli.BoolFlag{
+		Name:  "isolate",
+		Usage: "initialize the day again before each part, so parts that change its state don't affect each other",
+	},
+	&cli.BoolFlag{
+		Name:  "check-mutation",
+		Usage: "warn about any changes a part makes to the day's state",
+	},
+}, profileFlags...)
@@ -0,0 +1,99 @@
+package lib
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNdjsonReporter(t *testing.T) {
+	var buf bytes.Buffer
+	out, err := newReporter("ndjson", &buf)
+	require.NoError(t, err)
+
+	runParts(context.Background(), &stubDay{}, 99, NamedInput("", "sample"), "all", runOptions{}, out)
+	require.NoError(t, out.Close())
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 3)
+
+	var r Result
+	require.NoError(t, json.Unmarshal([]byte(lines[2]), &r))
+	assert.Equal(t, 99, r.Day)
+	assert.Equal(t, "part2", r.Part)
+	assert.Equal(t, "sample", r.Input)
+	assert.Equal(t, "World", r.Answer)
+	assert.Equal(t, 0, r.ExitStatus)
+}
+
+func TestUnknownFormat(t *testing.T) {
+	_, err := newReporter("xml", &bytes.Buffer{})
+	assert.Error(t, err)
+}
+
+// panicDay fails to parse any input
+type panicDay struct {
+	stubDay
+}
+
+func (d *panicDay) Init(input string) error {
+	var lines []string
+	_ = lines[1]
+	return nil
+}
+
+func TestTableReporter(t *testing.T) {
+	var buf bytes.Buffer
+	out, err := newReporter("table", &buf)
+	require.NoError(t, err)
+
+	runParts(context.Background(), &panicDay{}, 99, Input{Name: "bad.txt"}, "all", runOptions{}, out)
+	runParts(context.Background(), &stubDay{}, 99, Input{Name: "good.txt"}, "all", runOptions{}, out)
+	require.NoError(t, out.Close())
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 4)
+	assert.Regexp(t, `^bad.txt\s+init\s+ERROR: panic: runtime error`, lines[1])
+	assert.Regexp(t, `^good.txt\s+part1\s+Hello`, lines[2])
+	assert.Regexp(t, `^good.txt\s+part2\s+World`, lines[3])
+}
+
+func TestTextReporterErrors(t *testing.T) {
+	var out, errs bytes.Buffer
+	text := &textReporter{w: &out, errw: &errs}
+
+	results := runParts(context.Background(), &panicDay{}, 99, Input{Name: "bad.txt"}, "all", runOptions{}, text)
+	require.Len(t, results, 1)
+	assert.Equal(t, ExitInputError, results[0].ExitStatus)
+	assert.Contains(t, results[0].Stack, "panicDay")
+
+	assert.Regexp(t, `^======\nInitialized in \d+ms\n$`, out.String())
+	assert.True(t, strings.HasPrefix(errs.String(), "Error:\npanic: runtime error: index out of range [1] with length 0\n\ngoroutine "), errs.String())
+}
+
+func TestExitStatus(t *testing.T) {
+	assert.Equal(t, ExitInputError, exitStatus("init", errors.New("bad")))
+	assert.Equal(t, ExitInputError, exitStatus("part1", fmt.Errorf("line 3: %w", ErrInvalidInput)))
+	assert.Equal(t, ExitSolverError, exitStatus("part2", ErrNoSolution))
+	assert.Equal(t, ExitSolverError, exitStatus("part2", &PanicError{Value: "oops"}))
+	assert.Equal(t, ExitInputError, exitStatus("part2", &PanicError{Value: ErrInvalidInput}))
+	assert.Equal(t, ExitTimeout, exitStatus("init", fmt.Errorf("%w after 1s", ErrTimeout)))
+	assert.Equal(t, ExitInterrupted, exitStatus("part1", ErrInterrupted))
+
+	assert.Equal(t, 0, runExitStatus([]Result{{}, {Skipped: true}}))
+	assert.Equal(t, ExitTimeout, runExitStatus([]Result{{ExitStatus: ExitSolverError}, {ExitStatus: ExitTimeout}}))
+}
+
+func TestTestDayPanic(t *testing.T) {
+	Register(99, func() Day { return &panicDay{} })
+	defer delete(registry, 99)
+
+	assert.Equal(t, ExitInputError, testDay(99))
+}
@@ -0,0 +1,227 @@
+package lib
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+// runDays runs the selected parts of each day against its named input, with at most jobs parts
+// running at once. Each day's results are collected and reported to out in day order, as soon as
+// that day and the ones before it have finished, so concurrent days never interleave. The same goes
+// for the logs and progress the parts write with their context, which are held back and written to
+// logs along with the day's results.
+//
+// With splitParts, part 1 and part 2 run concurrently on separate instances of the day that are
+// each initialized from the input. That's only safe for days that don't share state between
+// instances, like package level variables.
+func runDays(ctx context.Context, days []int, name string, command string, jobs int, splitParts bool, opts runOptions, out reporter, logs io.Writer) ([]Result, error) {
+	factories := make([]DayFactory, len(days))
+	for i, number := range days {
+		factory, ok := registry[number]
+		if !ok {
+			return nil, fmt.Errorf("day %d is not registered", number)
+		}
+		factories[i] = factory
+	}
+
+	slots := make(chan struct{}, max(jobs, 1))
+	outputs := make([]*syncBuffer, len(days))
+	// the CPUs are shared between the parts running at once
+	ctx = withWorkers(ctx, max(runtime.GOMAXPROCS(0)/max(jobs, 1), 1))
+	// runTask runs the parts selected by command on a fresh instance of the day, once a slot is free
+	runTask := func(i int, command string) []Result {
+		ctx := ctx
+		if jobs > 1 || splitParts {
+			ctx = withOutput(ctx, outputs[i])
+		}
+		in := NamedInput(DayDir(days[i]), name)
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			cause := context.Cause(ctx)
+			return []Result{{Day: days[i], Part: "init", Input: in.Name, Error: cause.Error(), ExitStatus: exitStatus("init", cause)}}
+		}
+		defer func() { <-slots }()
+
+		return runParts(ctx, factories[i](), days[i], in, command, opts, nopReporter{})
+	}
+
+	done := make([]chan []Result, len(days))
+	for i := range days {
+		done[i] = make(chan []Result, 1)
+		outputs[i] = &syncBuffer{}
+		go func() {
+			if !splitParts || command != "all" {
+				done[i] <- runTask(i, command)
+				return
+			}
+
+			part2 := make(chan []Result, 1)
+			go func() { part2 <- runTask(i, "part2") }()
+			done[i] <- mergeParts(runTask(i, "part1"), <-part2)
+		}()
+	}
+
+	all := []Result{}
+	for i := range days {
+		results := <-done[i]
+		_, err := outputs[i].WriteTo(logs)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range results {
+			out.Report(r)
+			all = append(all, r)
+		}
+	}
+	return all, nil
+}
+
+// syncBuffer is a bytes.Buffer that can be written to from multiple goroutines
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) WriteTo(w io.Writer) (int64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.WriteTo(w)
+}
+
+type workersKey struct{}
+
+func withWorkers(ctx context.Context, n int) context.Context {
+	return context.WithValue(ctx, workersKey{}, n)
+}
+
+// WorkersFromContext returns how many goroutines a part should spread its work over: its share of
+// the CPUs when several parts run at once, or all of them otherwise
+func WorkersFromContext(ctx context.Context) int {
+	if n, ok := ctx.Value(workersKey{}).(int); ok {
+		return n
+	}
+	return runtime.GOMAXPROCS(0)
+}
+
+// mergeParts combines the results of running part 1 and part 2 on separate instances into one
+// run's results. Only part 1's Init is kept, and a failed Init for part 2 fails part 2.
+func mergeParts(part1 []Result, part2 []Result) []Result {
+	results := part1
+	if init := part2[0]; init.Failed() {
+		init.Part = "part2"
+		init.Error = "init failed: " + init.Error
+		init.DurationNs = 0
+		return append(results, init)
+	}
+	return append(results, part2[1:]...)
+}
+
+// writeDaysSummary prints the totals for a run of several days
+func writeDaysSummary(w io.Writer, results []Result, days int, jobs int, elapsed time.Duration) {
+	var work time.Duration
+	failed := 0
+	for _, r := range results {
+		work += r.Duration()
+		if r.Failed() {
+			failed++
+		}
+	}
+	fmt.Fprintf(w, "ran %d days with %d jobs in %v (%v of run time), %d failed\n",
+		days, jobs, elapsed.Round(time.Millisecond), work.Round(time.Millisecond), failed)
+}
+
+// runAllCommand runs every registered day concurrently, for `run all`
+func runAllCommand(c *cli.Context, command string, name string) error {
+	if c.IsSet("input") || c.IsSet("inputs") {
+		return fmt.Errorf("--input and --inputs can't be used to run every day")
+	}
+
+	jobs := c.Int("jobs")
+	concurrent := jobs > 1 || c.Bool("split-parts")
+	opts, err := optionsFromFlags(c)
+	if err != nil {
+		return err
+	}
+	if concurrent {
+		if opts.profile.enabled() {
+			return fmt.Errorf("profiles can only be recorded with --jobs 1")
+		}
+		// the heap is shared, so there's no telling which day used it
+		if opts.limits.memory > 0 {
+			return fmt.Errorf("--mem-limit can only be used with --jobs 1")
+		}
+	}
+
+	format := c.String("format")
+	if !c.IsSet("format") {
+		format = "table"
+	}
+	var out reporter = &textReporter{w: os.Stdout, errw: os.Stderr, days: true}
+	if format != "text" {
+		var err error
+		out, err = newReporter(format, os.Stdout)
+		if err != nil {
+			return err
+		}
+	}
+
+	ctx, stop := interruptContext(c.Context)
+	defer stop()
+
+	start := time.Now()
+	days := Days()
+	results, err := runDays(ctx, days, name, command, jobs, c.Bool("split-parts"), opts, out, os.Stderr)
+	if err != nil {
+		return err
+	}
+	err = out.Close()
+	if err != nil {
+		return err
+	}
+
+	writeDaysSummary(os.Stderr, results, len(days), jobs, time.Since(start))
+	if shouldRecord(c, opts, concurrent) {
+		for _, number := range days {
+			forDay := []Result{}
+			for _, r := range results {
+				if r.Day == number {
+					forDay = append(forDay, r)
+				}
+			}
+			recordRun(DayDir(number), []Input{NamedInput(DayDir(number), name)}, forDay)
+		}
+	}
+	if status := runExitStatus(results); status != 0 {
+		return cli.Exit("", status)
+	}
+	return nil
+}
+
+// runAllFlags are the runFlags plus the flags for running every day at once
+var runAllFlags = append(append([]cli.Flag{}, runFlags...),
+	&cli.IntFlag{
+		Name:    "jobs",
+		Aliases: []string{"j"},
+		Value:   runtime.GOMAXPROCS(0),
+		Usage:   "run up to `N` parts at once when running every day",
+	},
+	&cli.BoolFlag{
+		Name:  "split-parts",
+		Usage: "also run each day's parts concurrently, initializing the day separately for each",
+	},
+)
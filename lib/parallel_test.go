@@ -0,0 +1,136 @@
+package lib
+
+import (
+	"context"
+	"io"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sleepyDay takes a while to initialize, so it finishes after the days that start with it
+type sleepyDay struct {
+	stubDay
+}
+
+func (d *sleepyDay) Init(input string) error {
+	time.Sleep(50 * time.Millisecond)
+	return d.stubDay.Init(input)
+}
+
+// blockedDay's Init waits until it's released
+type blockedDay struct {
+	stubDay
+	release chan struct{}
+}
+
+func (d *blockedDay) Init(input string) error {
+	<-d.release
+	return d.stubDay.Init(input)
+}
+
+func TestRunDays(t *testing.T) {
+	Register(97, func() Day { return &sleepyDay{} })
+	Register(98, func() Day { return &stubDay{} })
+	defer delete(registry, 97)
+	defer delete(registry, 98)
+
+	var out strings.Builder
+	table := &tableReporter{w: &out}
+	results, err := runDays(context.Background(), []int{97, 98}, "input", "all", 2, true, runOptions{}, table, io.Discard)
+	require.NoError(t, err)
+	require.NoError(t, table.Close())
+
+	days := []int{}
+	for _, r := range results {
+		days = append(days, r.Day)
+	}
+	assert.Equal(t, []int{97, 97, 97, 98, 98, 98}, days)
+	assert.Contains(t, out.String(), "day  input  part   answer")
+	assert.Contains(t, out.String(), "98   input  part2  World")
+
+	_, err = runDays(context.Background(), []int{96}, "input", "all", 1, false, runOptions{}, nopReporter{}, io.Discard)
+	assert.Error(t, err)
+}
+
+// loggingDay logs from its parts, after waiting for delay
+type loggingDay struct {
+	stubDay
+	delay   time.Duration
+	workers int
+}
+
+func (d *loggingDay) Part1Context(ctx context.Context) (string, error) {
+	time.Sleep(d.delay)
+	d.workers = WorkersFromContext(ctx)
+	Log.WarnContext(ctx, "from part1", "input", d.input)
+	return d.Part1()
+}
+
+func (d *loggingDay) Part2Context(ctx context.Context) (string, error) {
+	time.Sleep(d.delay)
+	Log.WarnContext(ctx, "from part2", "input", d.input)
+	return d.Part2()
+}
+
+func TestRunDaysOutput(t *testing.T) {
+	slow := &loggingDay{delay: 50 * time.Millisecond}
+	Register(97, func() Day { return slow })
+	Register(98, func() Day { return &loggingDay{} })
+	defer delete(registry, 97)
+	defer delete(registry, 98)
+
+	var logs strings.Builder
+	_, err := runDays(context.Background(), []int{97, 98}, "input", "all", 2, false, runOptions{}, nopReporter{}, &logs)
+	require.NoError(t, err)
+
+	// day 98 logs first, but its messages are held back until day 97's are written
+	lines := strings.Split(strings.TrimSpace(logs.String()), "\n")
+	require.Len(t, lines, 4)
+	for i, expected := range []string{"day97/input.txt", "day97/input.txt", "day98/input.txt", "day98/input.txt"} {
+		assert.Contains(t, lines[i], expected)
+	}
+	assert.Contains(t, lines[0], "from part1")
+	assert.Contains(t, lines[1], "from part2")
+	assert.Equal(t, max(runtime.GOMAXPROCS(0)/2, 1), slow.workers)
+}
+
+func TestWorkersFromContext(t *testing.T) {
+	assert.Equal(t, runtime.GOMAXPROCS(0), WorkersFromContext(context.Background()))
+	assert.Equal(t, 3, WorkersFromContext(withWorkers(context.Background(), 3)))
+}
+
+func TestMergeParts(t *testing.T) {
+	part1 := []Result{{Part: "init"}, {Part: "part1", Answer: "Hello"}}
+
+	merged := mergeParts(part1, []Result{{Part: "init"}, {Part: "part2", Answer: "World"}})
+	assert.Equal(t, []Result{{Part: "init"}, {Part: "part1", Answer: "Hello"}, {Part: "part2", Answer: "World"}}, merged)
+
+	merged = mergeParts(part1, []Result{{Part: "init", Error: "bad", ExitStatus: 1, DurationNs: 5}})
+	assert.Equal(t, Result{Part: "part2", Error: "init failed: bad", ExitStatus: 1}, merged[2])
+}
+
+func TestRunDaysInterrupted(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	Register(97, func() Day { return &blockedDay{release: release} })
+	Register(98, func() Day { return &blockedDay{release: release} })
+	defer delete(registry, 97)
+	defer delete(registry, 98)
+
+	// day 98 is still waiting for day 97's slot when the run is interrupted
+	ctx, cancel := context.WithCancelCause(context.Background())
+	time.AfterFunc(20*time.Millisecond, func() { cancel(ErrInterrupted) })
+	results, err := runDays(ctx, []int{97, 98}, "input", "all", 1, false, runOptions{}, nopReporter{}, io.Discard)
+	require.NoError(t, err)
+
+	require.Len(t, results, 2)
+	for _, r := range results {
+		assert.Equal(t, "init", r.Part)
+		assert.Equal(t, ExitInterrupted, r.ExitStatus, "day %d", r.Day)
+	}
+}
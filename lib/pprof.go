@@ -0,0 +1,300 @@
+package lib
+
+import (
+	"compress/gzip"
+	"io"
+	"math"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// the runtime's heap and block profiles cover everything since the process started, and can't be
+// reset. To profile just one part, the records are read before and after it, and the difference
+// is written out in pprof's format (gzipped protobuf, see profile.proto in github.com/google/pprof).
+
+// profileSample is one stack in a profile, with a value for each of the profile's sample types
+type profileSample struct {
+	stack  []uintptr
+	values []int64
+}
+
+// sampleProfile is a profile to be written in pprof's format
+type sampleProfile struct {
+	// sampleTypes and periodType are pairs of type and unit, like {"alloc_space", "bytes"}
+	sampleTypes [][2]string
+	defaultType string
+	periodType  [2]string
+	period      int64
+	start       time.Time
+	samples     []profileSample
+}
+
+// memRecords reads the runtime's heap profile records, including the ones still in use
+func memRecords() []runtime.MemProfileRecord {
+	n, _ := runtime.MemProfile(nil, true)
+	for {
+		records := make([]runtime.MemProfileRecord, n+50)
+		var ok bool
+		n, ok = runtime.MemProfile(records, true)
+		if ok {
+			return records[:n]
+		}
+	}
+}
+
+// blockRecords reads the runtime's block profile records
+func blockRecords() []runtime.BlockProfileRecord {
+	n, _ := runtime.BlockProfile(nil)
+	for {
+		records := make([]runtime.BlockProfileRecord, n+50)
+		var ok bool
+		n, ok = runtime.BlockProfile(records)
+		if ok {
+			return records[:n]
+		}
+	}
+}
+
+// scaleHeapSample estimates the allocations a sampled count and size stand for, the same way
+// runtime/pprof does
+func scaleHeapSample(count, size, rate int64) (int64, int64) {
+	if count == 0 || size == 0 {
+		return 0, 0
+	}
+	if rate <= 1 {
+		return count, size
+	}
+	scale := 1 / (1 - math.Exp(-float64(size)/float64(count)/float64(rate)))
+	return int64(float64(count) * scale), int64(float64(size) * scale)
+}
+
+// hideRuntime drops the runtime's own frames, like mallocgc, from the top of a stack
+func hideRuntime(stack []uintptr) []uintptr {
+	for i, pc := range stack {
+		f := runtime.FuncForPC(pc)
+		if f == nil || (!strings.HasPrefix(f.Name(), "runtime.") && !strings.HasPrefix(f.Name(), "internal/runtime/")) {
+			return stack[i:]
+		}
+	}
+	// allocated by the runtime itself
+	return stack
+}
+
+// heapDiff is the heap profile of the allocations made between the before and after records
+func heapDiff(before, after []runtime.MemProfileRecord, rate int, start time.Time) sampleProfile {
+	earlier := map[[32]uintptr]runtime.MemProfileRecord{}
+	for _, r := range before {
+		earlier[r.Stack0] = r
+	}
+
+	p := sampleProfile{
+		sampleTypes: [][2]string{{"alloc_objects", "count"}, {"alloc_space", "bytes"}, {"inuse_objects", "count"}, {"inuse_space", "bytes"}},
+		defaultType: "alloc_space",
+		periodType:  [2]string{"space", "bytes"},
+		period:      int64(rate),
+		start:       start,
+	}
+	for _, r := range after {
+		e := earlier[r.Stack0]
+		allocs, allocBytes := r.AllocObjects-e.AllocObjects, r.AllocBytes-e.AllocBytes
+		if allocs == 0 {
+			// only frees of objects from before
+			continue
+		}
+		// objects from before that were freed don't count against this part
+		inUse, inUseBytes := max(allocs-(r.FreeObjects-e.FreeObjects), 0), max(allocBytes-(r.FreeBytes-e.FreeBytes), 0)
+
+		allocs, allocBytes = scaleHeapSample(allocs, allocBytes, int64(rate))
+		inUse, inUseBytes = scaleHeapSample(inUse, inUseBytes, int64(rate))
+		p.samples = append(p.samples, profileSample{stack: hideRuntime(r.Stack()), values: []int64{allocs, allocBytes, inUse, inUseBytes}})
+	}
+	return p
+}
+
+// blockDiff is the block profile of the blocking between the before and after records.
+// cyclesPerSecond converts the runtime's cycles to nanoseconds.
+func blockDiff(before, after []runtime.BlockProfileRecord, cyclesPerSecond float64, start time.Time) sampleProfile {
+	earlier := map[[32]uintptr]runtime.BlockProfileRecord{}
+	for _, r := range before {
+		earlier[r.Stack0] = r
+	}
+
+	p := sampleProfile{
+		sampleTypes: [][2]string{{"contentions", "count"}, {"delay", "nanoseconds"}},
+		defaultType: "delay",
+		periodType:  [2]string{"contentions", "count"},
+		period:      1,
+		start:       start,
+	}
+	for _, r := range after {
+		e := earlier[r.Stack0]
+		count, cycles := r.Count-e.Count, r.Cycles-e.Cycles
+		if count == 0 {
+			continue
+		}
+		delay := int64(float64(cycles) / cyclesPerSecond * float64(time.Second))
+		p.samples = append(p.samples, profileSample{stack: r.Stack(), values: []int64{count, delay}})
+	}
+	return p
+}
+
+// protoBuffer encodes protobuf fields
+type protoBuffer []byte
+
+func (b *protoBuffer) varint(v uint64) {
+	for v >= 0x80 {
+		*b = append(*b, byte(v)|0x80)
+		v >>= 7
+	}
+	*b = append(*b, byte(v))
+}
+
+func (b *protoBuffer) uint(field int, v uint64) {
+	if v == 0 {
+		return
+	}
+	b.varint(uint64(field) << 3)
+	b.varint(v)
+}
+
+func (b *protoBuffer) int(field int, v int64) {
+	b.uint(field, uint64(v))
+}
+
+func (b *protoBuffer) bytes(field int, data []byte) {
+	b.varint(uint64(field)<<3 | 2)
+	b.varint(uint64(len(data)))
+	*b = append(*b, data...)
+}
+
+func (b *protoBuffer) packed(field int, values []uint64) {
+	var packed protoBuffer
+	for _, v := range values {
+		packed.varint(v)
+	}
+	b.bytes(field, packed)
+}
+
+// write encodes the profile, resolving the stacks to functions and lines as it goes
+func (p sampleProfile) write(w io.Writer) error {
+	var out protoBuffer
+	table := []string{""}
+	stringIDs := map[string]int64{"": 0}
+	str := func(s string) int64 {
+		id, ok := stringIDs[s]
+		if !ok {
+			id = int64(len(table))
+			stringIDs[s] = id
+			table = append(table, s)
+		}
+		return id
+	}
+	valueType := func(t [2]string) protoBuffer {
+		var vt protoBuffer
+		vt.int(1, str(t[0]))
+		vt.int(2, str(t[1]))
+		return vt
+	}
+
+	for _, t := range p.sampleTypes {
+		out.bytes(1, valueType(t))
+	}
+
+	locationIDs := map[uintptr]uint64{}
+	functionIDs := map[string]uint64{}
+	var locations, functions []protoBuffer
+	location := func(pc uintptr) uint64 {
+		if id, ok := locationIDs[pc]; ok {
+			return id
+		}
+
+		var loc protoBuffer
+		id := uint64(len(locations) + 1)
+		loc.uint(1, id)
+		loc.uint(2, 1)
+		loc.uint(3, uint64(pc))
+		// a location has a line for each function inlined at it, innermost first
+		frames := runtime.CallersFrames([]uintptr{pc})
+		for {
+			frame, more := frames.Next()
+			fid, ok := functionIDs[frame.Function]
+			if !ok {
+				var fn protoBuffer
+				fid = uint64(len(functions) + 1)
+				fn.uint(1, fid)
+				fn.int(2, str(frame.Function))
+				fn.int(3, str(frame.Function))
+				fn.int(4, str(frame.File))
+				functions = append(functions, fn)
+				functionIDs[frame.Function] = fid
+			}
+
+			var line protoBuffer
+			line.uint(1, fid)
+			line.int(2, int64(frame.Line))
+			loc.bytes(4, line)
+			if !more {
+				break
+			}
+		}
+
+		locations = append(locations, loc)
+		locationIDs[pc] = id
+		return id
+	}
+
+	for _, s := range p.samples {
+		ids := make([]uint64, len(s.stack))
+		for i, pc := range s.stack {
+			ids[i] = location(pc)
+		}
+		values := make([]uint64, len(s.values))
+		for i, v := range s.values {
+			values[i] = uint64(v)
+		}
+
+		var sample protoBuffer
+		sample.packed(1, ids)
+		sample.packed(2, values)
+		out.bytes(2, sample)
+	}
+	for _, loc := range locations {
+		out.bytes(4, loc)
+	}
+	for _, fn := range functions {
+		out.bytes(5, fn)
+	}
+
+	// a single mapping for the whole binary, whose functions are already resolved
+	var mapping protoBuffer
+	mapping.uint(1, 1)
+	mapping.uint(3, math.MaxUint64)
+	if executable, err := os.Executable(); err == nil {
+		mapping.int(5, str(executable))
+	}
+	for _, field := range []int{7, 8, 9, 10} {
+		mapping.uint(field, 1)
+	}
+	out.bytes(3, mapping)
+
+	// the string table comes last, since the fields above add to it
+	periodType := valueType(p.periodType)
+	defaultType := str(p.defaultType)
+	for _, s := range table {
+		out.bytes(6, []byte(s))
+	}
+	out.int(9, p.start.UnixNano())
+	out.int(10, int64(time.Since(p.start)))
+	out.bytes(11, periodType)
+	out.int(12, p.period)
+	out.int(14, defaultType)
+
+	gz := gzip.NewWriter(w)
+	_, err := gz.Write(out)
+	if err != nil {
+		return err
+	}
+	return gz.Close()
+}
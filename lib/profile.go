@@ -0,0 +1,183 @@
+package lib
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+// profileOptions choose which profiles to record while each part runs
+type profileOptions struct {
+	dir   string
+	cpu   bool
+	mem   bool
+	block bool
+	trace bool
+}
+
+func profileOptionsFromFlags(c *cli.Context) profileOptions {
+	return profileOptions{
+		dir:   c.String("profile-dir"),
+		cpu:   c.Bool("cpuprofile"),
+		mem:   c.Bool("memprofile"),
+		block: c.Bool("blockprofile"),
+		trace: c.Bool("trace"),
+	}
+}
+
+func (p profileOptions) enabled() bool {
+	return p.cpu || p.mem || p.block || p.trace
+}
+
+// path names a profile file after the day and part, like day9-part2.cpu.pprof
+func (p profileOptions) path(number int, part string, kind string) string {
+	return filepath.Join(p.dir, fmt.Sprintf("day%d-%s.%s", number, part, kind))
+}
+
+// defaultMemProfileRate is the runtime's default. Allocations are only sampled while a part runs
+// with --memprofile, so its profile doesn't include the allocations made by CLI start up or Init.
+var defaultMemProfileRate = runtime.MemProfileRate
+
+func init() {
+	runtime.MemProfileRate = 0
+}
+
+// blockCyclesPerSecond is the rate the runtime measures blocking in, which it only reports in the
+// text form of the block profile
+var blockCyclesPerSecond = sync.OnceValue(func() float64 {
+	var buf bytes.Buffer
+	pprof.Lookup("block").WriteTo(&buf, 1)
+	for _, line := range strings.Split(buf.String(), "\n") {
+		if value, ok := strings.CutPrefix(line, "cycles/second="); ok {
+			cycles, err := strconv.ParseFloat(value, 64)
+			if err == nil && cycles > 0 {
+				return cycles
+			}
+		}
+	}
+	return float64(time.Second)
+})
+
+// start begins recording the selected profiles for one part, and returns the function that stops
+// them and writes the files
+func (p profileOptions) start(number int, part string) (func() error, error) {
+	stops := []func() error{}
+	// stopped in reverse, so writing one profile doesn't show up in those started before it
+	stop := func() error {
+		var errs []error
+		for _, s := range slices.Backward(stops) {
+			errs = append(errs, s())
+		}
+		return errors.Join(errs...)
+	}
+
+	if p.cpu {
+		f, err := os.Create(p.path(number, part, "cpu.pprof"))
+		if err != nil {
+			return nil, err
+		}
+		err = pprof.StartCPUProfile(f)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		stops = append(stops, func() error {
+			pprof.StopCPUProfile()
+			return f.Close()
+		})
+	}
+
+	if p.trace {
+		f, err := os.Create(p.path(number, part, "trace.out"))
+		if err != nil {
+			stop()
+			return nil, err
+		}
+		err = trace.Start(f)
+		if err != nil {
+			f.Close()
+			stop()
+			return nil, err
+		}
+		stops = append(stops, func() error {
+			trace.Stop()
+			return f.Close()
+		})
+	}
+
+	// the heap and block profiles are written as the difference from before the part, since the
+	// runtime's cover every part run so far
+	start := time.Now()
+	if p.block {
+		before := blockRecords()
+		runtime.SetBlockProfileRate(1)
+		stops = append(stops, func() error {
+			runtime.SetBlockProfileRate(0)
+			return writeProfile(p.path(number, part, "block.pprof"), blockDiff(before, blockRecords(), blockCyclesPerSecond(), start))
+		})
+	}
+
+	if p.mem {
+		// make sure everything from before is in the records
+		runtime.GC()
+		before := memRecords()
+		runtime.MemProfileRate = defaultMemProfileRate
+		stops = append(stops, func() error {
+			runtime.GC()
+			runtime.MemProfileRate = 0
+			return writeProfile(p.path(number, part, "mem.pprof"), heapDiff(before, memRecords(), defaultMemProfileRate, start))
+		})
+	}
+
+	return stop, nil
+}
+
+func writeProfile(path string, p sampleProfile) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	err = p.write(f)
+	if err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// profileFlags are shared by every command that runs a day's parts
+var profileFlags = []cli.Flag{
+	&cli.BoolFlag{
+		Name:  "cpuprofile",
+		Usage: "write a CPU profile of each part to dayN-partN.cpu.pprof",
+	},
+	&cli.BoolFlag{
+		Name:  "memprofile",
+		Usage: "write a heap profile of each part to dayN-partN.mem.pprof",
+	},
+	&cli.BoolFlag{
+		Name:  "blockprofile",
+		Usage: "write a blocking profile of each part to dayN-partN.block.pprof",
+	},
+	&cli.BoolFlag{
+		Name:  "trace",
+		Usage: "write an execution trace of each part to dayN-partN.trace.out",
+	},
+	&cli.StringFlag{
+		Name:  "profile-dir",
+		Value: ".",
+		Usage: "`DIR` to write profiles and traces to",
+	},
+}
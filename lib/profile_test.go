@@ -0,0 +1,92 @@
+package lib
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// profiledDay allocates and blocks in functions named after each part
+type profiledDay struct {
+	stubDay
+	kept [][]byte
+}
+
+//go:noinline
+func allocateForPart1(d *profiledDay) {
+	for range 32 {
+		d.kept = append(d.kept, make([]byte, 1<<20))
+	}
+}
+
+//go:noinline
+func allocateForPart2(d *profiledDay) {
+	for range 32 {
+		d.kept = append(d.kept, make([]byte, 1<<20))
+	}
+}
+
+//go:noinline
+func blockForPart1() {
+	done := make(chan struct{})
+	time.AfterFunc(time.Millisecond, func() { close(done) })
+	<-done
+}
+
+//go:noinline
+func blockForPart2() {
+	done := make(chan struct{})
+	time.AfterFunc(time.Millisecond, func() { close(done) })
+	<-done
+}
+
+func (d *profiledDay) Part1() (string, error) {
+	allocateForPart1(d)
+	blockForPart1()
+	return "Hello", nil
+}
+
+func (d *profiledDay) Part2() (string, error) {
+	allocateForPart2(d)
+	blockForPart2()
+	return "World", nil
+}
+
+// readProfile returns the uncompressed profile, whose string table holds the function names
+func readProfile(t *testing.T, path string) string {
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	require.NoError(t, err)
+	data, err := io.ReadAll(gz)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestProfilePerPart(t *testing.T) {
+	dir := t.TempDir()
+	opts := runOptions{profile: profileOptions{dir: dir, mem: true, block: true}}
+	results := runParts(context.Background(), &profiledDay{}, 99, Input{Name: "input"}, "all", opts, nopReporter{})
+	require.Len(t, results, 3)
+	assert.Equal(t, "World", results[2].Answer)
+
+	for _, kind := range []string{"mem.pprof", "block.pprof"} {
+		part1 := readProfile(t, filepath.Join(dir, "day99-part1."+kind))
+		part2 := readProfile(t, filepath.Join(dir, "day99-part2."+kind))
+
+		assert.True(t, strings.Contains(part2, "ForPart2"), "part2's %s should include part2", kind)
+		assert.False(t, strings.Contains(part2, "ForPart1"), "part2's %s shouldn't include part1", kind)
+		assert.True(t, strings.Contains(part1, "ForPart1"), "part1's %s should include part1", kind)
+		assert.False(t, strings.Contains(part1, "ForPart2"), "part1's %s shouldn't include part2", kind)
+	}
+}
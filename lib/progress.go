@@ -0,0 +1,158 @@
+package lib
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Progress lets a long running part report how far along it is. All of its methods are safe to
+// call from multiple goroutines.
+type Progress interface {
+	// SetTotal sets the number of units of work in the part
+	SetTotal(units int)
+	// Add marks units of work as done
+	Add(units int)
+	// SetLabel describes what the part is currently working on
+	SetLabel(label string)
+}
+
+type progressKey struct{}
+
+// ProgressFromContext returns the Progress the runner attached to a part's context. Parts run
+// outside the runner (like in tests) get one that ignores everything.
+func ProgressFromContext(ctx context.Context) Progress {
+	if p, ok := ctx.Value(progressKey{}).(Progress); ok {
+		return p
+	}
+	return nopProgress{}
+}
+
+func withProgress(ctx context.Context, p Progress) context.Context {
+	return context.WithValue(ctx, progressKey{}, p)
+}
+
+type nopProgress struct{}
+
+func (nopProgress) SetTotal(units int)    {}
+func (nopProgress) Add(units int)         {}
+func (nopProgress) SetLabel(label string) {}
+
+// progressTracker is the Progress handed out by the runner
+type progressTracker struct {
+	start   time.Time
+	total   atomic.Int64
+	done    atomic.Int64
+	mu      sync.Mutex
+	label   string
+	started atomic.Bool
+}
+
+func newProgressTracker() *progressTracker {
+	return &progressTracker{start: time.Now()}
+}
+
+func (p *progressTracker) SetTotal(units int) {
+	p.total.Store(int64(units))
+	p.started.Store(true)
+}
+
+func (p *progressTracker) Add(units int) {
+	p.done.Add(int64(units))
+	p.started.Store(true)
+}
+
+func (p *progressTracker) SetLabel(label string) {
+	p.mu.Lock()
+	p.label = label
+	p.mu.Unlock()
+	p.started.Store(true)
+}
+
+// String summarizes the progress, like "12/150 (8%) ETA 1m20s - machine 12"
+func (p *progressTracker) String() string {
+	total, done := p.total.Load(), p.done.Load()
+	elapsed := time.Since(p.start)
+
+	s := fmt.Sprintf("%d", done)
+	if total > 0 {
+		s = fmt.Sprintf("%d/%d (%d%%)", done, total, done*100/total)
+		if done > 0 && done < total {
+			eta := time.Duration(int64(elapsed) / done * (total - done))
+			s += fmt.Sprintf(" ETA %v", eta.Round(time.Second))
+		}
+	}
+	s += fmt.Sprintf(" elapsed %v", elapsed.Round(time.Second))
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.label != "" {
+		s += " - " + p.label
+	}
+	return s
+}
+
+// isTerminal reports whether f is attached to a terminal
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// renderProgress writes the tracker to w until stop is closed: as a line that's redrawn in place on
+// a terminal, or as a periodic log line otherwise. Nothing is written until the part reports
+// something, so quick parts stay quiet.
+func renderProgress(p *progressTracker, w io.Writer, tty bool, stop <-chan struct{}) {
+	interval := 10 * time.Second
+	if tty {
+		interval = 200 * time.Millisecond
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	drawn := false
+	for {
+		select {
+		case <-stop:
+			if drawn {
+				// clear the line so the result isn't printed after it
+				fmt.Fprint(w, "\r\033[K")
+			}
+			return
+		case <-ticker.C:
+			if !p.started.Load() {
+				continue
+			}
+			if tty {
+				fmt.Fprintf(w, "\r\033[K%s", p)
+				drawn = true
+			} else {
+				fmt.Fprintf(w, "progress: %s\n", p)
+			}
+		}
+	}
+}
+
+// trackProgress attaches a Progress to ctx and renders it until the returned function is called,
+// to the output attached to ctx or else stderr
+func trackProgress(ctx context.Context) (context.Context, func()) {
+	p := newProgressTracker()
+	w, tty := io.Writer(os.Stderr), isTerminal(os.Stderr)
+	if output, ok := outputFromContext(ctx); ok {
+		w, tty = output, false
+	}
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		renderProgress(p, w, tty, stop)
+	})
+
+	return withProgress(ctx, p), func() {
+		close(stop)
+		wg.Wait()
+	}
+}
@@ -0,0 +1,39 @@
+package lib
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgressTracker(t *testing.T) {
+	p := newProgressTracker()
+	ctx := withProgress(context.Background(), p)
+
+	progress := ProgressFromContext(ctx)
+	progress.SetTotal(100)
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Go(func() {
+			for range 5 {
+				progress.Add(1)
+				progress.SetLabel("working")
+			}
+		})
+	}
+	wg.Wait()
+
+	assert.Contains(t, p.String(), "50/100 (50%)")
+	assert.Contains(t, p.String(), "- working")
+}
+
+func TestProgressFromContextDefault(t *testing.T) {
+	progress := ProgressFromContext(context.Background())
+	assert.NotPanics(t, func() {
+		progress.SetTotal(1)
+		progress.Add(1)
+	})
+}
@@ -0,0 +1,56 @@
+package lib
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// DayFactory creates a fresh, uninitialized Day
+type DayFactory func() Day
+
+var registry = map[int]DayFactory{}
+
+// Register makes a day available to the multi-day runner. Each day's solution package calls
+// this from an init function.
+func Register(number int, factory DayFactory) {
+	if _, ok := registry[number]; ok {
+		panic(fmt.Sprintf("day %d is already registered", number))
+	}
+	registry[number] = factory
+}
+
+// Days returns the registered day numbers in ascending order
+func Days() []int {
+	days := make([]int, 0, len(registry))
+	for number := range registry {
+		days = append(days, number)
+	}
+	sort.Ints(days)
+	return days
+}
+
+// NewDay returns a fresh instance of a registered day
+func NewDay(number int) (Day, error) {
+	factory, ok := registry[number]
+	if !ok {
+		return nil, fmt.Errorf("day %d is not registered", number)
+	}
+	return factory(), nil
+}
+
+// dayNumber finds the number a day was registered under by comparing its type against the
+// registered days
+func dayNumber(d Day) (int, bool) {
+	for number, factory := range registry {
+		if reflect.TypeOf(factory()) == reflect.TypeOf(d) {
+			return number, true
+		}
+	}
+	return 0, false
+}
+
+// DayDir is the directory, relative to the module root, holding a day's code and inputs
+func DayDir(number int) string {
+	return fmt.Sprintf("day%d", number)
+}
@@ -0,0 +1,42 @@
+package lib
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubDay struct {
+	input string
+}
+
+func (d *stubDay) Init(input string) error {
+	d.input = input
+	return nil
+}
+
+func (d *stubDay) Part1() (string, error) {
+	return "Hello", nil
+}
+
+func (d *stubDay) Part2() (string, error) {
+	return "World", nil
+}
+
+func TestRegister(t *testing.T) {
+	Register(99, func() Day { return &stubDay{} })
+	defer delete(registry, 99)
+
+	assert.Contains(t, Days(), 99)
+	assert.Panics(t, func() { Register(99, func() Day { return &stubDay{} }) })
+
+	d1, err := NewDay(99)
+	require.NoError(t, err)
+	d2, err := NewDay(99)
+	require.NoError(t, err)
+	assert.NotSame(t, d1, d2)
+
+	_, err = NewDay(98)
+	assert.Error(t, err)
+}
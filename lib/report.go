@@ -0,0 +1,276 @@
+package lib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	// reportStart and reportEnd mark the section of the README that the report replaces
+	reportStart = "<!-- aoc report -->"
+	reportEnd   = "<!-- /aoc report -->"
+)
+
+// reportRow is one day's line in the report
+type reportRow struct {
+	Day int
+	// Stars counts the parts whose answer matches the one recorded in answers.json, or that the site
+	// accepted according to submissions.json
+	Stars   int
+	Answers [2]string
+	Init    time.Duration
+	Parts   [2]time.Duration
+	// Notes lists problems with the run, like a failed part or a missing input
+	Notes []string
+	// TimedOut is set if a part was stopped by the timeout, so the day is over budget however long it took
+	TimedOut bool
+	// Abandoned lists the parts that timed out but couldn't be stopped, since the day doesn't
+	// implement ContextDay. They keep running in the background, slowing down the days after.
+	Abandoned []string
+}
+
+// Total is the time taken by Init and both parts
+func (r reportRow) Total() time.Duration {
+	return r.Init + r.Parts[0] + r.Parts[1]
+}
+
+// reportDay runs a day against its real input and checks the answers against its answers and
+// submissions files
+func reportDay(ctx context.Context, number int, timeout time.Duration) (reportRow, error) {
+	row := reportRow{Day: number}
+	dir := DayDir(number)
+
+	in := NamedInput(dir, "input")
+	if !InputExists(in.Path) {
+		row.Notes = append(row.Notes, "no input")
+		return row, nil
+	}
+
+	d, err := NewDay(number)
+	if err != nil {
+		return row, err
+	}
+	answers, err := LoadAnswers(dir)
+	if err != nil {
+		return row, err
+	}
+	submissions, err := LoadSubmissions(dir)
+	if err != nil {
+		return row, err
+	}
+	_, stoppable := d.(ContextDay)
+
+	for _, r := range runParts(ctx, d, number, in, "all", runOptions{timeout: timeout}, nopReporter{}) {
+		if r.Part == "init" {
+			row.Init = r.Duration()
+			if r.Failed() {
+				row.Notes = append(row.Notes, "init failed: "+r.Error)
+			}
+			continue
+		}
+
+		i := 0
+		if r.Part == "part2" {
+			i = 1
+		}
+		row.Parts[i] = r.Duration()
+
+		switch {
+		case r.ExitStatus == ExitTimeout && stoppable:
+			row.Answers[i] = "timeout"
+			row.TimedOut = true
+			row.Notes = append(row.Notes, fmt.Sprintf("%s stopped after %v", r.Part, timeout))
+		case r.ExitStatus == ExitTimeout:
+			row.Answers[i] = "timeout"
+			row.TimedOut = true
+			row.Abandoned = append(row.Abandoned, r.Part)
+			row.Notes = append(row.Notes, fmt.Sprintf("%s abandoned after %v, but still running", r.Part, timeout))
+		case r.Failed():
+			row.Answers[i] = "error"
+			row.Notes = append(row.Notes, fmt.Sprintf("%s failed: %s", r.Part, r.Error))
+		case r.Skipped:
+			row.Answers[i] = "-"
+		default:
+			row.Answers[i] = r.Answer
+			expected, recorded := answers.Expected("input", r.Part)
+			submission, submitted := submissions.Find(r.Part, r.Answer)
+			switch {
+			case recorded && expected == r.Answer, submitted && submission.Verdict == VerdictCorrect:
+				row.Stars++
+			case recorded:
+				row.Notes = append(row.Notes, fmt.Sprintf("%s doesn't match answers.json", r.Part))
+			case submitted:
+				row.Notes = append(row.Notes, fmt.Sprintf("%s was rejected as %s", r.Part, submission.Verdict))
+			default:
+				row.Notes = append(row.Notes, fmt.Sprintf("%s is unverified", r.Part))
+			}
+		}
+	}
+	return row, nil
+}
+
+// reportOptions control how the report is written
+type reportOptions struct {
+	// budget is how long a day can take in total before it's flagged, if non-zero
+	budget time.Duration
+	// mask hides the answers, which are meant to be kept private
+	mask bool
+}
+
+// formatDuration rounds a duration to a readable precision, or returns "-" if the phase didn't run
+func formatDuration(d time.Duration) string {
+	switch {
+	case d == 0:
+		return "-"
+	case d < time.Second:
+		return d.Round(time.Microsecond).String()
+	default:
+		return d.Round(time.Millisecond).String()
+	}
+}
+
+// writeReport writes the rows as a markdown table, followed by the total stars
+func writeReport(w io.Writer, rows []reportRow, opts reportOptions) {
+	fmt.Fprintln(w, "| Day | Stars | Part 1 | Part 2 | Init | Part 1 time | Part 2 time | Total | Notes |")
+	fmt.Fprintln(w, "|----:|-------|--------|--------|-----:|------------:|------------:|------:|-------|")
+
+	stars := 0
+	for _, row := range rows {
+		stars += row.Stars
+
+		answers := row.Answers
+		if opts.mask {
+			for i, answer := range answers {
+				if answer != "" && answer != "-" && answer != "error" && answer != "timeout" {
+					answers[i] = "●●●●●●"
+				}
+			}
+		}
+
+		total := formatDuration(row.Total())
+		notes := row.Notes
+		if opts.budget > 0 && (row.TimedOut || row.Total() > opts.budget) {
+			total = "**" + total + "**"
+			notes = append([]string{"over " + opts.budget.String() + " budget"}, notes...)
+		}
+
+		fmt.Fprintf(w, "| %d | %s | %s | %s | %s | %s | %s | %s | %s |\n",
+			row.Day,
+			strings.Repeat("★", row.Stars)+strings.Repeat("☆", 2-row.Stars),
+			markdownCell(answers[0]),
+			markdownCell(answers[1]),
+			formatDuration(row.Init),
+			formatDuration(row.Parts[0]),
+			formatDuration(row.Parts[1]),
+			total,
+			markdownCell(strings.Join(notes, "; ")),
+		)
+	}
+
+	fmt.Fprintf(w, "\n%d of %d stars\n", stars, 2*len(rows))
+}
+
+// markdownCell escapes text so it stays in one table cell
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", " ")
+}
+
+// updateReadme replaces the report section of the markdown file at path, or appends one if there
+// isn't one yet
+func updateReadme(path string, report string) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	readme := string(data)
+	section := reportStart + "\n" + report + reportEnd
+
+	start := strings.Index(readme, reportStart)
+	end := strings.Index(readme, reportEnd)
+	switch {
+	case start >= 0 && end > start:
+		readme = readme[:start] + section + readme[end+len(reportEnd):]
+	case start >= 0 || end >= 0:
+		return fmt.Errorf("%s has only one of the %s and %s markers", path, reportStart, reportEnd)
+	default:
+		if readme != "" && !strings.HasSuffix(readme, "\n") {
+			readme += "\n"
+		}
+		readme += "\n## Report\n\n" + section + "\n"
+	}
+
+	return os.WriteFile(path, []byte(readme), 0644)
+}
+
+// reportDays runs each of the days in turn for the report
+func reportDays(ctx context.Context, days []int, timeout time.Duration) ([]reportRow, error) {
+	rows := make([]reportRow, 0, len(days))
+	// the abandoned parts of earlier days, which are still competing for the CPU
+	running := []string{}
+	for _, number := range days {
+		row, err := reportDay(ctx, number, timeout)
+		if err != nil {
+			return nil, err
+		}
+		if len(running) > 0 {
+			row.Notes = append(row.Notes, "timed while "+strings.Join(running, ", ")+" kept running")
+		}
+		for _, part := range row.Abandoned {
+			running = append(running, fmt.Sprintf("day %d %s", number, part))
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// report runs the days and prints the report, also writing it to the README at readme if that's set
+func report(ctx context.Context, days []int, timeout time.Duration, opts reportOptions, readme string) error {
+	rows, err := reportDays(ctx, days, timeout)
+	if err != nil {
+		return err
+	}
+
+	var table strings.Builder
+	writeReport(&table, rows, opts)
+	fmt.Print(table.String())
+
+	if readme == "" {
+		return nil
+	}
+	err = updateReadme(readme, table.String())
+	if err != nil {
+		return err
+	}
+	fmt.Printf("\nupdated %s\n", readme)
+	return nil
+}
+
+// reportFlags are the flags for the report command
+var reportFlags = []cli.Flag{
+	&cli.DurationFlag{
+		Name:  "budget",
+		Value: time.Second,
+		Usage: "flag days that take longer than this in total, or 0 to not flag any",
+	},
+	&cli.BoolFlag{
+		Name:  "mask",
+		Usage: "hide the answers",
+	},
+	&cli.StringFlag{
+		Name:  "readme",
+		Usage: "also write the report to a section of `FILE`, e.g. README.md",
+	},
+	&cli.DurationFlag{
+		Name:  "timeout",
+		Usage: "stop each part after this long, e.g. 30s (the --budget by default, since a part that takes longer is over it anyway)",
+	},
+}
@@ -0,0 +1,127 @@
+package lib
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportDay(t *testing.T) {
+	t.Chdir(t.TempDir())
+	Register(99, func() Day { return &stubDay{} })
+	defer delete(registry, 99)
+
+	row, err := reportDay(context.Background(), 99, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"no input"}, row.Notes)
+
+	writeFile(t, "day99/input.txt", "")
+	answers := Answers{}
+	answers.Record("input", "part1", "Hello")
+	answers.Record("input", "part2", "Universe")
+	require.NoError(t, answers.Save("day99"))
+
+	row, err = reportDay(context.Background(), 99, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, row.Stars)
+	assert.Equal(t, [2]string{"Hello", "World"}, row.Answers)
+	assert.Equal(t, []string{"part2 doesn't match answers.json"}, row.Notes)
+
+	// the site's verdict counts too
+	submissions := &SubmissionHistory{Parts: map[string][]Submission{
+		"part2": {{Answer: "World", Verdict: VerdictCorrect, Time: time.Now()}},
+	}}
+	require.NoError(t, submissions.Save("day99"))
+	row, err = reportDay(context.Background(), 99, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 2, row.Stars)
+	assert.Empty(t, row.Notes)
+
+	Register(98, func() Day { return &slowDay{stopped: make(chan struct{})} })
+	defer delete(registry, 98)
+	writeFile(t, "day98/input.txt", "")
+	submissions = &SubmissionHistory{Parts: map[string][]Submission{
+		"part1": {{Answer: "Hello", Verdict: VerdictTooLow, Time: time.Now()}},
+	}}
+	require.NoError(t, submissions.Save("day98"))
+
+	row, err = reportDay(context.Background(), 98, 10*time.Millisecond)
+	require.NoError(t, err)
+	assert.True(t, row.TimedOut)
+	assert.Empty(t, row.Abandoned)
+	assert.Equal(t, [2]string{"Hello", "timeout"}, row.Answers)
+	assert.Equal(t, []string{"part1 was rejected as too low", "part2 stopped after 10ms"}, row.Notes)
+}
+
+// stubbornDay's part 2 can't be stopped
+type stubbornDay struct {
+	stubDay
+}
+
+func (d *stubbornDay) Part2() (string, error) {
+	time.Sleep(100 * time.Millisecond)
+	return d.stubDay.Part2()
+}
+
+func TestReportDaysAbandoned(t *testing.T) {
+	t.Chdir(t.TempDir())
+	Register(98, func() Day { return &stubbornDay{} })
+	Register(99, func() Day { return &stubDay{} })
+	defer delete(registry, 98)
+	defer delete(registry, 99)
+	writeFile(t, "day98/input.txt", "")
+	writeFile(t, "day99/input.txt", "")
+
+	rows, err := reportDays(context.Background(), []int{98, 99}, 10*time.Millisecond)
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+
+	assert.Equal(t, []string{"part2"}, rows[0].Abandoned)
+	assert.Equal(t, []string{"part1 is unverified", "part2 abandoned after 10ms, but still running"}, rows[0].Notes)
+	assert.Equal(t, []string{"part1 is unverified", "part2 is unverified", "timed while day 98 part2 kept running"}, rows[1].Notes)
+}
+
+func TestWriteReport(t *testing.T) {
+	rows := []reportRow{
+		{Day: 1, Stars: 2, Answers: [2]string{"42", "a|b"}, Init: time.Millisecond, Parts: [2]time.Duration{time.Millisecond, time.Millisecond}},
+		{Day: 2, Stars: 1, Answers: [2]string{"7", "-"}, Init: time.Second, Parts: [2]time.Duration{time.Second, 0}},
+	}
+
+	var out strings.Builder
+	writeReport(&out, rows, reportOptions{budget: time.Second})
+	lines := strings.Split(out.String(), "\n")
+	assert.Equal(t, `| 1 | ★★ | 42 | a\|b | 1ms | 1ms | 1ms | 3ms |  |`, lines[2])
+	assert.Equal(t, "| 2 | ★☆ | 7 | - | 1s | 1s | - | **2s** | over 1s budget |", lines[3])
+	assert.Equal(t, "3 of 4 stars", lines[5])
+
+	out.Reset()
+	writeReport(&out, []reportRow{{Day: 3, Answers: [2]string{"timeout", ""}, Parts: [2]time.Duration{time.Millisecond, 0}, TimedOut: true}}, reportOptions{budget: time.Second, mask: true})
+	assert.Contains(t, out.String(), "| 3 | ☆☆ | timeout |  | - | 1ms | - | **1ms** | over 1s budget |")
+
+	out.Reset()
+	writeReport(&out, rows, reportOptions{mask: true})
+	assert.Contains(t, out.String(), "| 2 | ★☆ | ●●●●●● | - | 1s | 1s | - | 2s |  |")
+}
+
+func TestUpdateReadme(t *testing.T) {
+	path := t.TempDir() + "/README.md"
+	writeFile(t, path, "# Title")
+
+	require.NoError(t, updateReadme(path, "first\n"))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "# Title\n\n## Report\n\n<!-- aoc report -->\nfirst\n<!-- /aoc report -->\n", string(data))
+
+	require.NoError(t, updateReadme(path, "second\n"))
+	data, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "# Title\n\n## Report\n\n<!-- aoc report -->\nsecond\n<!-- /aoc report -->\n", string(data))
+
+	writeFile(t, path, "<!-- /aoc report -->")
+	assert.Error(t, updateReadme(path, "third\n"))
+}
@@ -1,9 +1,14 @@
 package lib
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"path/filepath"
+	"runtime/debug"
 	"time"
 
 	"github.com/urfave/cli/v2"
@@ -15,86 +20,429 @@ type Day interface {
 	Part2() (string, error)
 }
 
-func initialize(d Day, c *cli.Context) {
+// Input is where a day reads its puzzle input from
+type Input struct {
+	// Name identifies the input in results, like "sample"
+	Name string
+	// Path is passed to the day's Init
+	Path string
+}
+
+// NamedInput is the input called name in a day's directory, like "sample" for dir/sample.txt
+func NamedInput(dir string, name string) Input {
+	return Input{Name: name, Path: filepath.Join(dir, name+".txt")}
+}
+
+// Exit statuses for a run where a phase failed. When several phases fail, the highest is used.
+const (
+	// ExitSolverError means a part returned an error or panicked
+	ExitSolverError = 1
+	// ExitInputError means the input couldn't be read or parsed: Init failed, or a part returned
+	// ErrInvalidInput
+	ExitInputError = 2
+	// ExitTimeout means a part ran past --timeout, or the day ran past --time-limit
+	ExitTimeout = 3
+	// ExitMemoryLimit means the day used more memory than --mem-limit
+	ExitMemoryLimit = 4
+	// ExitInterrupted means the run was cancelled with Ctrl-C
+	ExitInterrupted = 130
+)
+
+// PanicError is a panic recovered from a day, with the stack trace of where it happened
+type PanicError struct {
+	Value any
+	Stack string
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap returns the value the day panicked with if it's an error, so panic(err) can still be matched
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// writeStack prints the stack of a recovered panic, if err is one
+func writeStack(w io.Writer, err error) {
+	var panicErr *PanicError
+	if errors.As(err, &panicErr) {
+		fmt.Fprintf(w, "\n%s", panicErr.Stack)
+	}
+}
+
+// callSafely calls f, turning a panic into a *PanicError so one bad input doesn't take down the process
+func callSafely(f func() (string, error)) (answer string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{Value: r, Stack: string(debug.Stack())}
+		}
+	}()
+	return f()
+}
+
+// exitStatus picks the exit status for a phase that failed with err
+func exitStatus(part string, err error) int {
+	switch {
+	case errors.Is(err, ErrInterrupted):
+		return ExitInterrupted
+	case errors.Is(err, ErrMemoryLimit):
+		return ExitMemoryLimit
+	case errors.Is(err, ErrTimeout), errors.Is(err, ErrTimeLimit):
+		return ExitTimeout
+	case part == "init" || errors.Is(err, ErrInvalidInput):
+		return ExitInputError
+	default:
+		return ExitSolverError
+	}
+}
+
+// runPhase times f and records its outcome
+func runPhase(number int, part string, in Input, f func() (string, error)) Result {
 	start := time.Now()
+	answer, err := callSafely(f)
 
-	file := "input"
-	if c.Args().Len() > 0 {
-		file = c.Args().Get(0)
+	r := Result{
+		Day:        number,
+		Part:       part,
+		Input:      in.Name,
+		DurationNs: time.Since(start).Nanoseconds(),
 	}
-	err := d.Init("/" + file + ".txt")
-	if err != nil {
-		panic(err)
+	var panicErr *PanicError
+	if errors.Is(err, ErrNoPart) {
+		r.Skipped = true
+	} else if err != nil {
+		r.Error = err.Error()
+		r.ExitStatus = exitStatus(part, err)
+		if errors.As(err, &panicErr) {
+			r.Stack = panicErr.Stack
+		}
+	} else {
+		r.Answer = answer
+	}
+	return r
+}
+
+// runExitStatus returns the highest exit status of the results, or 0 if every phase succeeded
+func runExitStatus(results []Result) int {
+	status := 0
+	for _, r := range results {
+		status = max(status, r.ExitStatus)
 	}
+	return status
+}
 
-	fmt.Println("======")
-	fmt.Printf("Initialized in %dms\n", time.Since(start).Milliseconds())
+// runOptions control how runParts executes a day's parts
+type runOptions struct {
+	// timeout stops each part once it passes, if non-zero
+	timeout time.Duration
+	// progress renders the progress parts report to stderr
+	progress bool
+	// profile records profiles of each part
+	profile profileOptions
+	// isolate initializes a fresh instance of the day for each part, so one part can't change
+	// what the other sees. Days that implement Cloner are always isolated.
+	isolate bool
+	// checkMutation warns about any changes a part makes to the day's state
+	checkMutation bool
+	// solver names the variant to run for each part (see VariantDay), or is empty for the default
+	solver string
+	// limits stop the whole day if it uses too much memory or time
+	limits limitOptions
 }
 
-func runPart1WithTimings(d Day) {
-	start := time.Now()
+// optionsFromFlags reads the runOptions from a command's runFlags
+func optionsFromFlags(c *cli.Context) (runOptions, error) {
+	opts := runOptions{
+		timeout:  c.Duration("timeout"),
+		progress: true,
+		profile:  profileOptionsFromFlags(c),
 
-	result, err := d.Part1()
+		isolate:       c.Bool("isolate"),
+		checkMutation: c.Bool("check-mutation"),
+		solver:        c.String("solver"),
+		limits:        limitOptions{time: c.Duration("time-limit")},
+	}
+	if c.IsSet("mem-limit") {
+		var err error
+		opts.limits.memory, err = parseBytes(c.String("mem-limit"))
+		if err != nil {
+			return opts, err
+		}
+	}
+	return opts, nil
+}
 
-	fmt.Println("======")
-	fmt.Printf("Part 1 completed in %dms\n", time.Since(start).Milliseconds())
+// runParts initializes the day from the input and runs the parts selected by command
+// ("part1", "part2" or "all"), reporting each phase as it completes. The parts are skipped
+// if Init fails.
+func runParts(ctx context.Context, d Day, number int, in Input, command string, opts runOptions, out reporter) []Result {
+	var limits *watchdog
+	if opts.limits.enabled() {
+		ctx, limits = opts.limits.start(ctx)
+		defer limits.stop()
+	}
 
-	if err != nil {
-		fmt.Printf("Error:\n%v", err)
-	} else {
-		fmt.Printf("Result: %s\n", result)
+	result := runPhase(number, "init", in, func() (string, error) {
+		return runWithContext(ctx, func(context.Context) (string, error) {
+			return "", d.Init(in.Path)
+		}, 0)
+	})
+	if limits != nil {
+		result.PeakHeapBytes = limits.takePeak()
+	}
+	out.Report(result)
+	results := []Result{result}
+	if result.Failed() {
+		return results
 	}
+
+	first := true
+	for _, part := range []string{"part1", "part2"} {
+		if command != part && command != "all" {
+			continue
+		}
+
+		pd, err := partDay(d, number, in, opts.isolate, first)
+		first = false
+		if err != nil {
+			result = Result{Day: number, Part: part, Input: in.Name, Error: "init failed: " + err.Error(), ExitStatus: ExitInputError}
+			out.Report(result)
+			results = append(results, result)
+			continue
+		}
+		variant := findVariant(pd, part, opts.solver)
+		var before daySnapshot
+		if opts.checkMutation {
+			before = snapshotState(pd)
+		}
+
+		partCtx, stopProgress := ctx, func() {}
+		if opts.progress {
+			partCtx, stopProgress = trackProgress(ctx)
+		}
+		result = runPhase(number, part, in, func() (string, error) {
+			stopProfiles, err := opts.profile.start(number, part)
+			if err != nil {
+				return "", err
+			}
+			defer func() {
+				err := stopProfiles()
+				if err != nil {
+					Log.ErrorContext(ctx, "failed to write profiles", "part", part, "err", err)
+				}
+			}()
+
+			return runWithContext(partCtx, variant.Solve, opts.timeout)
+		})
+		stopProgress()
+		if opts.solver != "" {
+			result.Solver = variant.Name
+		}
+		if limits != nil {
+			result.PeakHeapBytes = limits.takePeak()
+		}
+
+		// a part that failed may still be running in the background
+		if opts.checkMutation && !result.Failed() {
+			checkMutation(ctx, before, pd, number, part)
+		}
+
+		out.Report(result)
+		results = append(results, result)
+	}
+
+	return results
 }
 
-func runPart2WithTimings(d Day) {
-	start := time.Now()
+// commandInputs picks the inputs for a command: every file matching the --inputs glob, the --input
+// flag if it's set ("-" for stdin), or the input called name in the day's directory
+func commandInputs(c *cli.Context, dir string, name string) ([]Input, error) {
+	if pattern := c.String("inputs"); pattern != "" {
+		paths, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+		if len(paths) == 0 {
+			return nil, fmt.Errorf("no inputs match %q", pattern)
+		}
+
+		inputs := make([]Input, len(paths))
+		for i, path := range paths {
+			inputs[i] = Input{Name: path, Path: path}
+		}
+		return inputs, nil
+	}
+
+	path := c.String("input")
+	switch path {
+	case "":
+		return []Input{NamedInput(dir, name)}, nil
+	case "-":
+		inline, err := ReaderInput(os.Stdin)
+		if err != nil {
+			return nil, err
+		}
+		return []Input{{Name: "stdin", Path: inline}}, nil
+	default:
+		return []Input{{Name: path, Path: path}}, nil
+	}
+}
+
+// runCommand runs the selected parts of a day against each of the command's inputs, writing the
+// results in the format chosen by the --format flag. A batch of inputs defaults to a table rather
+// than text. SIGINT cancels the running part. If any phase fails, the exit status says why (see
+// ExitSolverError and the rest).
+func runCommand(c *cli.Context, d Day, number int, dir string, command string, name string) error {
+	inputs, err := commandInputs(c, dir, name)
+	if err != nil {
+		return err
+	}
+	// an input read from stdin is held in memory until the run is done with it
+	defer func() {
+		for _, in := range inputs {
+			ReleaseInput(in.Path)
+		}
+	}()
+	err = checkSolver(d, command, c.String("solver"))
+	if err != nil {
+		return err
+	}
 
-	result, err := d.Part2()
+	format := c.String("format")
+	if c.IsSet("inputs") && !c.IsSet("format") {
+		format = "table"
+	}
+	out, err := newReporter(format, os.Stdout)
+	if err != nil {
+		return err
+	}
 
-	fmt.Println("======")
-	fmt.Printf("Part 2 completed in %dms\n", time.Since(start).Milliseconds())
+	ctx, stop := interruptContext(c.Context)
+	defer stop()
 
+	opts, err := optionsFromFlags(c)
 	if err != nil {
-		fmt.Printf("Error:\n%v", err)
-	} else {
-		fmt.Printf("Result: %s\n", result)
+		return err
 	}
+
+	results := []Result{}
+	for _, in := range inputs {
+		results = append(results, runParts(ctx, d, number, in, command, opts, out)...)
+	}
+	err = out.Close()
+	if err != nil {
+		return err
+	}
+	if shouldRecord(c, opts, false) {
+		recordRun(dir, inputs, results)
+	}
+
+	if status := runExitStatus(results); status != 0 {
+		return cli.Exit("", status)
+	}
+	return nil
+}
+
+// inputArg returns the input name at position i of the command's arguments, defaulting to "input"
+func inputArg(c *cli.Context, i int) string {
+	if c.Args().Len() > i {
+		return c.Args().Get(i)
+	}
+	return "input"
 }
 
 func Run(day Day) {
+	number, _ := dayNumber(day)
+
 	app := &cli.App{
+		Flags:                  logFlags,
+		Before:                 configureLogging,
+		UseShortOptionHandling: true,
 		Commands: []*cli.Command{
 			{
 				Name:  "part1",
 				Usage: "run part 1",
+				Flags: runFlags,
 				Action: func(c *cli.Context) error {
-					initialize(day, c)
-					runPart1WithTimings(day)
-					return nil
+					return runCommand(c, day, number, "", "part1", inputArg(c, 0))
 				},
 			},
 			{
 				Name:  "part2",
 				Usage: "run part 2",
+				Flags: runFlags,
 				Action: func(c *cli.Context) error {
-					initialize(day, c)
-					fmt.Println()
-					runPart1WithTimings(day)
-					return nil
+					return runCommand(c, day, number, "", "part2", inputArg(c, 0))
 				},
 			},
 			{
 				Name:  "all",
 				Usage: "run both parts",
+				Flags: runFlags,
+				Action: func(c *cli.Context) error {
+					return runCommand(c, day, number, "", "all", inputArg(c, 0))
+				},
+			},
+			{
+				Name:      "watch",
+				Usage:     "rebuild and rerun whenever the day's code or inputs change",
+				ArgsUsage: "[part1|part2|all] [INPUT]",
+				Flags:     watchFlags,
+				Action: func(c *cli.Context) error {
+					return watchCommand(c, ".", 0)
+				},
+			},
+			{
+				Name:      "verify",
+				Usage:     "check answers against answers.json",
+				ArgsUsage: "[INPUT...]",
+				Flags:     verifyFlags,
 				Action: func(c *cli.Context) error {
-					initialize(day, c)
-					fmt.Println()
-					runPart1WithTimings(day)
-					fmt.Println()
-					runPart2WithTimings(day)
+					ctx, stop := interruptContext(c.Context)
+					defer stop()
+
+					failures, status, err := verify(ctx, day, number, "", c.Args().Slice(), c.Bool("record"))
+					if err != nil {
+						return err
+					}
+					if failures > 0 {
+						return cli.Exit(fmt.Sprintf("%d answers did not match", failures), status)
+					}
 					return nil
 				},
 			},
+			{
+				Name:      "crosscheck",
+				Usage:     "run every solver for each part and check that they agree",
+				ArgsUsage: "[part1|part2|all] [INPUT]",
+				Flags:     crosscheckFlags,
+				Action: func(c *cli.Context) error {
+					return crosscheckCommand(c, day, number, "", 0)
+				},
+			},
+			{
+				Name:  "history",
+				Usage: "show how long past runs took and flag the parts that got slower",
+				Flags: historyFlags,
+				Action: func(c *cli.Context) error {
+					return historyCommand(c, []int{number}, []string{""})
+				},
+			},
+			{
+				Name:      "bench",
+				Usage:     "time repeated runs of both parts",
+				ArgsUsage: "[INPUT]",
+				Flags:     benchFlags,
+				Action: func(c *cli.Context) error {
+					ctx, stop := interruptContext(c.Context)
+					defer stop()
+
+					opts := runOptions{timeout: c.Duration("timeout"), solver: c.String("solver")}
+					return bench(ctx, os.Stdout, day, number, "", inputArg(c, 0), c.Int("count"), c.Int("warmup"), opts)
+				},
+			},
 		},
 	}
 
@@ -0,0 +1,172 @@
+package lib
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/urfave/cli/v2"
+)
+
+// aocMain is the multi-day binary that imports every day's solution package
+const aocMain = "cmd/aoc/main.go"
+
+// templateData is available to .tmpl files in a custom template
+type templateData struct {
+	// Day is the new day's number
+	Day int
+	// Dir is the new day's directory, like "day13"
+	Dir string
+	// Module is the module path from go.mod
+	Module string
+}
+
+// skippedTemplateFiles are the template's own puzzle data and records of its runs, which shouldn't
+// be copied
+var skippedTemplateFiles = map[string]bool{
+	"input.txt":                   true,
+	"input.txt" + EncryptedSuffix: true,
+	"sample.txt":                  true,
+	AnswersFile:                   true,
+	HistoryFile:                   true,
+	SubmissionsFile:               true,
+}
+
+// modulePath reads the module path from the go.mod in the current directory
+func modulePath() (string, error) {
+	data, err := os.ReadFile("go.mod")
+	if err != nil {
+		return "", fmt.Errorf("run from the module root: %w", err)
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		if module, ok := strings.CutPrefix(line, "module "); ok {
+			return strings.TrimSpace(module), nil
+		}
+	}
+	return "", errors.New("go.mod has no module line")
+}
+
+// scaffold creates a new day's directory from a template directory. Files ending in .tmpl are
+// rendered with text/template and templateData. Other files are copied with references to the
+// template's own day (e.g. "day0" and lib.Register(0, ...)) changed to the new day.
+func scaffold(number int, templateDir string) error {
+	dir := DayDir(number)
+	if _, err := os.Stat(dir); err == nil {
+		return fmt.Errorf("%s already exists", dir)
+	}
+
+	module, err := modulePath()
+	if err != nil {
+		return err
+	}
+	data := templateData{Day: number, Dir: dir, Module: module}
+
+	// the template's own day number, from its directory name
+	templateName := filepath.Base(templateDir)
+	templateNumber, err := strconv.Atoi(strings.TrimPrefix(templateName, "day"))
+	if err != nil {
+		return fmt.Errorf("template directory %q should be named like day0", templateDir)
+	}
+	dayName := regexp.MustCompile(`\b` + templateName + `\b`)
+	register := fmt.Sprintf("lib.Register(%d,", templateNumber)
+
+	err = filepath.WalkDir(templateDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(templateDir, path)
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return os.MkdirAll(filepath.Join(dir, rel), 0755)
+		}
+		if skippedTemplateFiles[rel] {
+			return nil
+		}
+
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		if strings.HasSuffix(rel, ".tmpl") {
+			rel = strings.TrimSuffix(rel, ".tmpl")
+			tmpl, err := template.New(rel).Parse(string(contents))
+			if err != nil {
+				return err
+			}
+			var buf bytes.Buffer
+			err = tmpl.Execute(&buf, data)
+			if err != nil {
+				return err
+			}
+			contents = buf.Bytes()
+		} else {
+			contents = dayName.ReplaceAll(contents, []byte(dir))
+			contents = bytes.ReplaceAll(contents, []byte(register), fmt.Appendf(nil, "lib.Register(%d,", number))
+		}
+
+		return os.WriteFile(filepath.Join(dir, rel), contents, 0644)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, name := range []string{"sample.txt", "input.txt"} {
+		err = os.WriteFile(filepath.Join(dir, name), nil, 0644)
+		if err != nil {
+			return err
+		}
+	}
+
+	return addToAocMain(module, dir)
+}
+
+// addToAocMain adds the new day's solution package to the imports of the multi-day binary
+func addToAocMain(module string, dir string) error {
+	contents, err := os.ReadFile(aocMain)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	line := fmt.Sprintf("\t_ %q\n", module+"/"+dir+"/solution")
+	if bytes.Contains(contents, []byte(line)) {
+		return nil
+	}
+
+	imports := []byte("import (\n")
+	i := bytes.Index(contents, imports)
+	if i < 0 {
+		return fmt.Errorf("couldn't find the imports in %s", aocMain)
+	}
+	i += len(imports)
+	contents = append(contents[:i:i], append([]byte(line), contents[i:]...)...)
+
+	contents, err = format.Source(contents)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(aocMain, contents, 0644)
+}
+
+// newFlags are the flags for the new command
+var newFlags = []cli.Flag{
+	&cli.StringFlag{
+		Name:  "template",
+		Value: "day0",
+		Usage: "`DIR` to generate the day from",
+	},
+}
@@ -0,0 +1,57 @@
+package lib
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, path string, contents string) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0644))
+}
+
+func TestScaffold(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	writeFile(t, "go.mod", "module example.com/aoc\n")
+	writeFile(t, "cmd/aoc/main.go", "package main\n\nimport (\n\t\"example.com/aoc/lib\"\n)\n\nfunc main() {\n\tlib.Main()\n}\n")
+	writeFile(t, "templates/day0/main.go", "package main\n\nimport \"example.com/aoc/day0/solution\"\n")
+	writeFile(t, "templates/day0/solution/solution.go", "func init() {\n\tlib.Register(0, newDay)\n}\n")
+	writeFile(t, "templates/day0/README.md.tmpl", "# Day {{.Day}} ({{.Module}}/{{.Dir}})\n")
+	writeFile(t, "templates/day0/input.txt", "not copied")
+	for _, name := range []string{"input.txt" + EncryptedSuffix, AnswersFile, HistoryFile, SubmissionsFile} {
+		writeFile(t, "templates/day0/"+name, "not copied")
+	}
+
+	require.NoError(t, scaffold(13, "templates/day0"))
+
+	contents, err := os.ReadFile("day13/main.go")
+	require.NoError(t, err)
+	assert.Contains(t, string(contents), `"example.com/aoc/day13/solution"`)
+
+	contents, err = os.ReadFile("day13/solution/solution.go")
+	require.NoError(t, err)
+	assert.Contains(t, string(contents), "lib.Register(13, newDay)")
+
+	contents, err = os.ReadFile("day13/README.md")
+	require.NoError(t, err)
+	assert.Equal(t, "# Day 13 (example.com/aoc/day13)\n", string(contents))
+
+	contents, err = os.ReadFile("day13/input.txt")
+	require.NoError(t, err)
+	assert.Empty(t, contents)
+	assert.FileExists(t, "day13/sample.txt")
+	for _, name := range []string{"input.txt" + EncryptedSuffix, AnswersFile, HistoryFile, SubmissionsFile} {
+		assert.NoFileExists(t, "day13/"+name)
+	}
+
+	contents, err = os.ReadFile("cmd/aoc/main.go")
+	require.NoError(t, err)
+	assert.Contains(t, string(contents), `_ "example.com/aoc/day13/solution"`)
+
+	assert.ErrorContains(t, scaffold(13, "templates/day0"), "already exists")
+}
@@ -0,0 +1,222 @@
+package lib
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+// server runs the registered days on inputs posted to it
+type server struct {
+	// timeout limits how long a request waits for a free slot and runs its part
+	timeout time.Duration
+	// maxInput is the largest input accepted, in bytes
+	maxInput int64
+	// slots caps how many days run at once. A part that times out keeps its slot until it actually
+	// stops, since days that don't implement ContextDay carry on in the background.
+	slots chan struct{}
+}
+
+func newServer(timeout time.Duration, maxInput int64, concurrency int) *server {
+	return &server{
+		timeout:  timeout,
+		maxInput: maxInput,
+		slots:    make(chan struct{}, max(concurrency, 1)),
+	}
+}
+
+func (s *server) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /days", s.listDays)
+	mux.HandleFunc("POST /days/{n}/parts/{p}", s.runPart)
+	return mux
+}
+
+// serveResponse is the JSON returned for a part run through the server
+type serveResponse struct {
+	Day        int    `json:"day"`
+	Part       string `json:"part"`
+	Answer     string `json:"answer,omitempty"`
+	Error      string `json:"error,omitempty"`
+	Skipped    bool   `json:"skipped,omitempty"`
+	InitNs     int64  `json:"init_ns"`
+	DurationNs int64  `json:"duration_ns"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func (s *server) listDays(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string][]int{"days": Days()})
+}
+
+// runPart runs one part of a day on the request body. The response is a serveResponse, with a
+// status that says what went wrong: 404 for an unknown day, 413 for an input over the size limit,
+// 422 if the input couldn't be parsed, 500 if the part failed, 503 if no slot came free in time
+// and 504 if the part timed out.
+func (s *server) runPart(w http.ResponseWriter, r *http.Request) {
+	number, err := parseDay(r.PathValue("n"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	part := "part" + strings.TrimPrefix(r.PathValue("p"), "part")
+	if part != "part1" && part != "part2" {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("unknown part %q", r.PathValue("p")))
+		return
+	}
+	d, err := NewDay(number)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, s.maxInput))
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		writeError(w, http.StatusRequestEntityTooLarge, fmt.Errorf("the input is over the limit of %d bytes", s.maxInput))
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeoutCause(r.Context(), s.timeout, fmt.Errorf("%w after %v", ErrTimeout, s.timeout))
+	defer cancel()
+
+	select {
+	case s.slots <- struct{}{}:
+	case <-ctx.Done():
+		writeError(w, http.StatusServiceUnavailable, errors.New("too many days are running, try again later"))
+		return
+	}
+
+	// saved inputs don't end with a newline, but uploaded ones usually do
+	in := Input{Name: "upload", Path: StringInput(strings.TrimSuffix(string(body), "\n"))}
+	done := make(chan []Result, 1)
+	go func() {
+		defer func() { <-s.slots }()
+		defer ReleaseInput(in.Path)
+
+		initResult := runPhase(number, "init", in, func() (string, error) {
+			return "", d.Init(in.Path)
+		})
+		if initResult.Failed() {
+			done <- []Result{initResult}
+			return
+		}
+		done <- []Result{initResult, runPhase(number, part, in, func() (string, error) {
+			answer, err := partSolver(d, part)(ctx)
+			if err != nil && ctx.Err() != nil && errors.Is(err, ctx.Err()) {
+				return "", context.Cause(ctx)
+			}
+			return answer, err
+		})}
+	}()
+
+	var results []Result
+	select {
+	case results = <-done:
+	case <-ctx.Done():
+		if errors.Is(context.Cause(ctx), ErrTimeout) {
+			writeError(w, http.StatusGatewayTimeout, context.Cause(ctx))
+		}
+		// otherwise the client went away
+		return
+	}
+
+	resp := serveResponse{Day: number, Part: part, InitNs: results[0].DurationNs}
+	if results[0].Failed() {
+		resp.Error = "init failed: " + results[0].Error
+		writeJSON(w, http.StatusUnprocessableEntity, resp)
+		return
+	}
+
+	result := results[1]
+	resp.Answer, resp.Error, resp.Skipped, resp.DurationNs = result.Answer, result.Error, result.Skipped, result.DurationNs
+	status := http.StatusOK
+	switch {
+	case result.ExitStatus == ExitTimeout:
+		status = http.StatusGatewayTimeout
+	case result.ExitStatus == ExitInputError:
+		status = http.StatusUnprocessableEntity
+	case result.Failed():
+		status = http.StatusInternalServerError
+	}
+	writeJSON(w, status, resp)
+}
+
+// serve runs the server on addr until ctx is done
+func serve(ctx context.Context, addr string, s *server) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("serving %d days on http://%s\n", len(Days()), listener.Addr())
+
+	srv := &http.Server{
+		Handler:           s.handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdown)
+	}()
+
+	err = srv.Serve(listener)
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// serveFlags are the flags for the serve command
+var serveFlags = []cli.Flag{
+	&cli.StringFlag{
+		Name:  "addr",
+		Value: "localhost:8080",
+		Usage: "listen on `ADDRESS`",
+	},
+	&cli.DurationFlag{
+		Name:  "timeout",
+		Value: 30 * time.Second,
+		Usage: "give up on a request after this long, including waiting for a free slot",
+	},
+	&cli.Int64Flag{
+		Name:  "max-input",
+		Value: 1 << 20,
+		Usage: "reject inputs over `BYTES` long",
+	},
+	&cli.IntFlag{
+		Name:  "max-concurrent",
+		Value: 2,
+		Usage: "run at most `N` days at once",
+	},
+}
+
+// serveCommand runs the server with the settings from serveFlags until it's interrupted
+func serveCommand(c *cli.Context) error {
+	ctx, stop := interruptContext(c.Context)
+	defer stop()
+
+	s := newServer(c.Duration("timeout"), c.Int64("max-input"), c.Int("max-concurrent"))
+	return serve(ctx, c.String("addr"), s)
+}
@@ -0,0 +1,86 @@
+package lib
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func post(t *testing.T, url string, body string) (int, serveResponse) {
+	resp, err := http.Post(url, "text/plain", strings.NewReader(body))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var r serveResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&r))
+	return resp.StatusCode, r
+}
+
+func TestServe(t *testing.T) {
+	Register(97, func() Day { return &panicDay{} })
+	Register(98, func() Day { return &slowDay{stopped: make(chan struct{})} })
+	Register(99, func() Day { return &stubDay{} })
+	defer delete(registry, 97)
+	defer delete(registry, 98)
+	defer delete(registry, 99)
+
+	srv := httptest.NewServer(newServer(50*time.Millisecond, 16, 1).handler())
+	defer srv.Close()
+
+	status, r := post(t, srv.URL+"/days/99/parts/2", "some input\n")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "World", r.Answer)
+	assert.Equal(t, "part2", r.Part)
+
+	status, r = post(t, srv.URL+"/days/99/parts/part1", "")
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "Hello", r.Answer)
+
+	status, _ = post(t, srv.URL+"/days/99/parts/3", "")
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	status, r = post(t, srv.URL+"/days/96/parts/1", "")
+	assert.Equal(t, http.StatusNotFound, status)
+	assert.Equal(t, "day 96 is not registered", r.Error)
+
+	status, _ = post(t, srv.URL+"/days/99/parts/1", strings.Repeat("x", 17))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, status)
+
+	status, r = post(t, srv.URL+"/days/97/parts/1", "")
+	assert.Equal(t, http.StatusUnprocessableEntity, status)
+	assert.Contains(t, r.Error, "init failed: panic: runtime error")
+
+	status, r = post(t, srv.URL+"/days/98/parts/2", "")
+	assert.Equal(t, http.StatusGatewayTimeout, status)
+	assert.Equal(t, "timed out after 50ms", r.Error)
+}
+
+func TestServeBusy(t *testing.T) {
+	Register(99, func() Day { return &stubDay{} })
+	defer delete(registry, 99)
+
+	s := newServer(20*time.Millisecond, 16, 1)
+	srv := httptest.NewServer(s.handler())
+	defer srv.Close()
+
+	s.slots <- struct{}{}
+	status, _ := post(t, srv.URL+"/days/99/parts/1", "")
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+
+	<-s.slots
+	status, _ = post(t, srv.URL+"/days/99/parts/1", "")
+	assert.Equal(t, http.StatusOK, status)
+
+	resp, err := http.Get(srv.URL + "/days")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	var days map[string][]int
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&days))
+	assert.Contains(t, days["days"], 99)
+}
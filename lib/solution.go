@@ -0,0 +1,87 @@
+package lib
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+)
+
+// ErrNoPart is returned by a part that the puzzle doesn't have, like part 2 of the last day. The
+// runner reports the part as skipped rather than failed.
+var ErrNoPart = errors.New("the puzzle has no such part")
+
+// ErrNoSolution is returned by a part that searched everything without finding an answer, which
+// usually means a bug in the search rather than in the input
+var ErrNoSolution = errors.New("no solution found")
+
+// ErrInvalidInput is returned when the input isn't in the format the day expects. Init errors
+// are always treated as input errors, so this is for problems that parts find later.
+var ErrInvalidInput = errors.New("invalid input")
+
+// Solution is a typed alternative to Day. The parsed input is passed to each part instead of living
+// in the day's fields, and answers are returned as values (ints, *big.Int, strings, ...) that the
+// runner formats. Use NewTypedDay to run one as a Day.
+type Solution[In any, Out any] interface {
+	Parse(r io.Reader) (In, error)
+	Part1(in In) (Out, error)
+	Part2(in In) (Out, error)
+}
+
+// typedDay adapts a Solution to the Day interface
+type typedDay[In any, Out any] struct {
+	solution Solution[In, Out]
+	input    In
+}
+
+// NewTypedDay wraps a Solution so it can be registered and run like any other Day. The input is
+// read with ReadFile, so inline inputs and stdin work too.
+func NewTypedDay[In any, Out any](solution Solution[In, Out]) Day {
+	return &typedDay[In, Out]{solution: solution}
+}
+
+func (d *typedDay[In, Out]) Init(input string) error {
+	contents, err := ReadFile(input)
+	if err != nil {
+		return err
+	}
+
+	d.input, err = d.solution.Parse(strings.NewReader(contents))
+	return err
+}
+
+func (d *typedDay[In, Out]) Part1() (string, error) {
+	answer, err := d.solution.Part1(d.input)
+	if err != nil {
+		return "", err
+	}
+	return FormatAnswer(answer)
+}
+
+func (d *typedDay[In, Out]) Part2() (string, error) {
+	answer, err := d.solution.Part2(d.input)
+	if err != nil {
+		return "", err
+	}
+	return FormatAnswer(answer)
+}
+
+// FormatAnswer turns a typed answer into the string that gets submitted
+func FormatAnswer(answer any) (string, error) {
+	switch a := answer.(type) {
+	case string:
+		return a, nil
+	case *big.Int:
+		if a == nil {
+			return "", errors.New("answer is a nil *big.Int")
+		}
+		return a.String(), nil
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprintf("%d", a), nil
+	case fmt.Stringer:
+		return a.String(), nil
+	default:
+		return "", fmt.Errorf("can't format an answer of type %T", answer)
+	}
+}
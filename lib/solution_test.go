@@ -0,0 +1,62 @@
+package lib
+
+import (
+	"io"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// wordCount is a Solution counting words in part 1, with no part 2
+type wordCount struct{}
+
+func (wordCount) Parse(r io.Reader) ([]string, error) {
+	data, err := io.ReadAll(r)
+	return strings.Fields(string(data)), err
+}
+
+func (wordCount) Part1(words []string) (int, error) {
+	return len(words), nil
+}
+
+func (wordCount) Part2(words []string) (int, error) {
+	return 0, ErrNoPart
+}
+
+func TestTypedDay(t *testing.T) {
+	d := NewTypedDay[[]string, int](wordCount{})
+	require.NoError(t, d.Init(StringInput("one two\nthree")))
+
+	result, err := d.Part1()
+	require.NoError(t, err)
+	assert.Equal(t, "3", result)
+
+	_, err = d.Part2()
+	assert.ErrorIs(t, err, ErrNoPart)
+
+	r := runPhase(99, "part2", Input{Name: "inline"}, d.Part2)
+	assert.True(t, r.Skipped)
+	assert.False(t, r.Failed())
+}
+
+func TestFormatAnswer(t *testing.T) {
+	huge, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+
+	for answer, expected := range map[any]string{
+		42:         "42",
+		int64(-7):  "-7",
+		uint8(255): "255",
+		"abc":      "abc",
+		huge:       "123456789012345678901234567890",
+	} {
+		result, err := FormatAnswer(answer)
+		require.NoError(t, err)
+		assert.Equal(t, expected, result)
+	}
+
+	_, err := FormatAnswer(1.5)
+	assert.Error(t, err)
+}
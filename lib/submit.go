@@ -0,0 +1,266 @@
+package lib
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+// SubmissionsFile is the name of the file in each day's directory that keeps its submission history
+const SubmissionsFile = "submissions.json"
+
+// Verdict is the site's response to a submitted answer
+type Verdict string
+
+const (
+	VerdictCorrect    Verdict = "correct"
+	VerdictTooHigh    Verdict = "too high"
+	VerdictTooLow     Verdict = "too low"
+	VerdictWrong      Verdict = "wrong"
+	VerdictWait       Verdict = "wait"
+	VerdictWrongLevel Verdict = "wrong level"
+)
+
+// SubmitResult is the parsed response to a submitted answer
+type SubmitResult struct {
+	Verdict Verdict
+	// Wait is how long the site wants us to wait before submitting again
+	Wait time.Duration
+	// Message is the text of the response
+	Message string
+}
+
+var (
+	articlePattern = regexp.MustCompile(`(?s)<article[^>]*>(.*?)</article>`)
+	tagPattern     = regexp.MustCompile(`<[^>]+>`)
+	waitPattern    = regexp.MustCompile(`You have (?:(\d+)m )?(\d+)s left to wait`)
+	spacePattern   = regexp.MustCompile(`\s+`)
+)
+
+// parseSubmitResponse reads the verdict out of the page returned after submitting an answer
+func parseSubmitResponse(page string) SubmitResult {
+	message := page
+	if match := articlePattern.FindStringSubmatch(page); match != nil {
+		message = match[1]
+	}
+	message = strings.TrimSpace(spacePattern.ReplaceAllString(tagPattern.ReplaceAllString(message, ""), " "))
+	result := SubmitResult{Message: message}
+
+	switch {
+	case strings.Contains(message, "That's the right answer"):
+		result.Verdict = VerdictCorrect
+	case strings.Contains(message, "answer too recently"):
+		result.Verdict = VerdictWait
+	case strings.Contains(message, "too high"):
+		result.Verdict = VerdictTooHigh
+	case strings.Contains(message, "too low"):
+		result.Verdict = VerdictTooLow
+	case strings.Contains(message, "not the right answer"):
+		result.Verdict = VerdictWrong
+	default:
+		result.Verdict = VerdictWrongLevel
+	}
+
+	// both rate limiting and wrong answers say how long to wait
+	if match := waitPattern.FindStringSubmatch(message); match != nil {
+		minutes, _ := strconv.Atoi(match[1])
+		seconds, _ := strconv.Atoi(match[2])
+		result.Wait = time.Duration(minutes)*time.Minute + time.Duration(seconds)*time.Second
+	} else if strings.Contains(message, "wait one minute") {
+		result.Wait = time.Minute
+	}
+
+	return result
+}
+
+// SubmitAnswer posts the answer to one part (level 1 or 2) of a day's puzzle
+func (c *Client) SubmitAnswer(ctx context.Context, number int, level int, answer string) (SubmitResult, error) {
+	form := url.Values{
+		"level":  {strconv.Itoa(level)},
+		"answer": {answer},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.dayURL(number)+"/answer", strings.NewReader(form.Encode()))
+	if err != nil {
+		return SubmitResult{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	page, err := c.do(req)
+	if err != nil {
+		return SubmitResult{}, err
+	}
+	return parseSubmitResponse(page), nil
+}
+
+// Submission is one answer sent to the site
+type Submission struct {
+	Answer  string    `json:"answer"`
+	Verdict Verdict   `json:"verdict"`
+	Time    time.Time `json:"time"`
+}
+
+// SubmissionHistory is every answer submitted for a day, by part ("part1", "part2")
+type SubmissionHistory struct {
+	Parts map[string][]Submission `json:"parts"`
+	// WaitUntil is when the site will accept another answer
+	WaitUntil time.Time `json:"wait_until,omitzero"`
+}
+
+// LoadSubmissions reads the submission history from a day's directory
+func LoadSubmissions(dir string) (*SubmissionHistory, error) {
+	history := &SubmissionHistory{Parts: map[string][]Submission{}}
+
+	data, err := os.ReadFile(filepath.Join(dir, SubmissionsFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return history, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(data, history)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", SubmissionsFile, err)
+	}
+	if history.Parts == nil {
+		history.Parts = map[string][]Submission{}
+	}
+	return history, nil
+}
+
+// Save writes the submission history to a day's directory
+func (h *SubmissionHistory) Save(dir string) error {
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, SubmissionsFile), append(data, '\n'), 0644)
+}
+
+// Find returns the earlier submission of the same answer, if there was one
+func (h *SubmissionHistory) Find(part string, answer string) (Submission, bool) {
+	for _, s := range h.Parts[part] {
+		if s.Answer == answer && s.Verdict != VerdictWait && s.Verdict != VerdictWrongLevel {
+			return s, true
+		}
+	}
+	return Submission{}, false
+}
+
+// Bounds returns the highest answer known to be too low and the lowest known to be too high, for
+// parts with numeric answers
+func (h *SubmissionHistory) Bounds(part string) (low int64, hasLow bool, high int64, hasHigh bool) {
+	for _, s := range h.Parts[part] {
+		value, err := strconv.ParseInt(s.Answer, 10, 64)
+		if err != nil {
+			continue
+		}
+		if s.Verdict == VerdictTooLow && (!hasLow || value > low) {
+			low, hasLow = value, true
+		}
+		if s.Verdict == VerdictTooHigh && (!hasHigh || value < high) {
+			high, hasHigh = value, true
+		}
+	}
+	return
+}
+
+// checkBounds describes the earlier wrong answer that the answer is out of bounds of, like "10 was
+// too low", or returns "" if it's within the bounds
+func (h *SubmissionHistory) checkBounds(part string, answer string) string {
+	value, err := strconv.ParseInt(answer, 10, 64)
+	if err != nil {
+		return ""
+	}
+
+	low, hasLow, high, hasHigh := h.Bounds(part)
+	if hasLow && value <= low {
+		return fmt.Sprintf("%d was %s", low, VerdictTooLow)
+	}
+	if hasHigh && value >= high {
+		return fmt.Sprintf("%d was %s", high, VerdictTooHigh)
+	}
+	return ""
+}
+
+// submit sends an answer for a day's part unless it was already submitted (or force is set) or the
+// part is solved, then records the outcome. An answer outside the bounds set by earlier wrong
+// answers is still sent, with a warning. A correct answer is also saved to the day's answers file
+// for the real input.
+func submit(ctx context.Context, client *Client, number int, dir string, part string, answer string, force bool) error {
+	history, err := LoadSubmissions(dir)
+	if err != nil {
+		return err
+	}
+
+	if wait := time.Until(history.WaitUntil); wait > 0 {
+		return fmt.Errorf("%w: wait %v before submitting again", ErrRateLimited, wait.Round(time.Second))
+	}
+	for _, s := range history.Parts[part] {
+		if s.Verdict == VerdictCorrect {
+			return fmt.Errorf("%s is already solved with %s", part, s.Answer)
+		}
+	}
+	if previous, ok := history.Find(part, answer); ok && !force {
+		return fmt.Errorf("%s was already submitted on %s: %s (use --force to submit it again)", answer, previous.Time.Format(time.DateTime), previous.Verdict)
+	}
+	if known := history.checkBounds(part, answer); known != "" {
+		Log.WarnContext(ctx, "answer is outside the known bounds", "day", number, "part", part, "answer", answer, "known", known)
+	}
+
+	level := 1
+	if part == "part2" {
+		level = 2
+	}
+	result, err := client.SubmitAnswer(ctx, number, level, answer)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	history.Parts[part] = append(history.Parts[part], Submission{Answer: answer, Verdict: result.Verdict, Time: now})
+	if result.Wait > 0 {
+		history.WaitUntil = now.Add(result.Wait)
+	}
+	err = history.Save(dir)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("day %d %s: %s: %s\n", number, part, answer, result.Verdict)
+	switch result.Verdict {
+	case VerdictCorrect:
+		answers, err := LoadAnswers(dir)
+		if err != nil {
+			return err
+		}
+		answers.Record("input", part, answer)
+		return answers.Save(dir)
+	case VerdictWait:
+		return fmt.Errorf("%w: wait %v before submitting again", ErrRateLimited, result.Wait)
+	case VerdictWrongLevel:
+		return fmt.Errorf("%s wasn't accepted: %s", part, result.Message)
+	default:
+		return cli.Exit("", 1)
+	}
+}
+
+// submitFlags are the flags for the submit command
+var submitFlags = append([]cli.Flag{
+	&cli.BoolFlag{
+		Name:  "force",
+		Usage: "submit even if the same answer was already rejected",
+	},
+}, clientFlags...)
@@ -0,0 +1,94 @@
+package lib
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func answerPage(message string) string {
+	return fmt.Sprintf("<html><body><main>\n<article><p>%s</p></article>\n</main></body></html>", message)
+}
+
+func TestParseSubmitResponse(t *testing.T) {
+	cases := []struct {
+		message string
+		verdict Verdict
+		wait    time.Duration
+	}{
+		{"That's the right answer!  You are <span class=\"day-success\">one gold star</span> closer.", VerdictCorrect, 0},
+		{"That's not the right answer; your answer is too high.  Please wait one minute before trying again.", VerdictTooHigh, time.Minute},
+		{"That's not the right answer; your answer is too low.  You have 4m 32s left to wait.", VerdictTooLow, 4*time.Minute + 32*time.Second},
+		{"That's not the right answer.  Please wait one minute before trying again.", VerdictWrong, time.Minute},
+		{"You gave an answer too recently; you have to wait after submitting an answer before trying again.  You have 32s left to wait.", VerdictWait, 32 * time.Second},
+		{"You don't seem to be solving the right level.  Did you already complete it?", VerdictWrongLevel, 0},
+	}
+
+	for _, c := range cases {
+		result := parseSubmitResponse(answerPage(c.message))
+		assert.Equal(t, c.verdict, result.Verdict, c.message)
+		assert.Equal(t, c.wait, result.Wait, c.message)
+	}
+}
+
+func TestSubmit(t *testing.T) {
+	submitted := []string{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /2025/day/1/answer", func(w http.ResponseWriter, r *http.Request) {
+		answer := r.FormValue("answer")
+		submitted = append(submitted, r.FormValue("level")+":"+answer)
+
+		switch answer {
+		case "100", "150":
+			w.Write([]byte(answerPage("That's not the right answer; your answer is too high.")))
+		case "10", "5":
+			w.Write([]byte(answerPage("That's not the right answer; your answer is too low.")))
+		default:
+			w.Write([]byte(answerPage("That's the right answer!")))
+		}
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewClient(Config{Session: "valid", BaseURL: server.URL, Year: 2025})
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	assert.Error(t, submit(ctx, client, 1, dir, "part1", "100", false))
+	assert.Error(t, submit(ctx, client, 1, dir, "part1", "10", false))
+
+	// already known to be wrong, so it isn't sent again unless forced
+	assert.ErrorContains(t, submit(ctx, client, 1, dir, "part1", "100", false), "already submitted")
+	assert.Error(t, submit(ctx, client, 1, dir, "part1", "100", true))
+
+	// outside the bounds the wrong answers set, which only warns
+	var logged strings.Builder
+	defer func(l *slog.Logger) { Log = l }(Log)
+	Log = slog.New(slog.NewTextHandler(&logged, nil))
+	assert.Error(t, submit(ctx, client, 1, dir, "part1", "150", false))
+	assert.Error(t, submit(ctx, client, 1, dir, "part1", "5", false))
+	assert.Contains(t, logged.String(), `msg="answer is outside the known bounds" day=1 part=part1 answer=150 known="100 was too high"`)
+	assert.Contains(t, logged.String(), `answer=5 known="10 was too low"`)
+
+	require.NoError(t, submit(ctx, client, 1, dir, "part1", "42", false))
+	assert.ErrorContains(t, submit(ctx, client, 1, dir, "part1", "43", false), "already solved")
+
+	assert.Equal(t, []string{"1:100", "1:10", "1:100", "1:150", "1:5", "1:42"}, submitted)
+
+	answers, err := LoadAnswers(dir)
+	require.NoError(t, err)
+	expected, _ := answers.Expected("input", "part1")
+	assert.Equal(t, "42", expected)
+
+	history, err := LoadSubmissions(dir)
+	require.NoError(t, err)
+	assert.Len(t, history.Parts["part1"], 6)
+}
@@ -0,0 +1,178 @@
+package lib
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+// DefaultVariant is the name of the solver made up of the day's Part1 and Part2
+const DefaultVariant = "default"
+
+// Variant is a named implementation of one of a day's parts, like "bruteforce" or "ilp"
+type Variant struct {
+	Name  string
+	Solve func(ctx context.Context) (string, error)
+}
+
+// VariantDay is implemented by days with more than one way to solve a part. Part1 and Part2 are
+// always available as the "default" variant, and Variants returns the others for a part ("part1"
+// or "part2"), or nil if it only has the default.
+type VariantDay interface {
+	Day
+	Variants(part string) []Variant
+}
+
+// variants returns every solver for a part of the day, starting with the default
+func variants(d Day, part string) []Variant {
+	all := []Variant{{Name: DefaultVariant, Solve: partSolver(d, part)}}
+	if vd, ok := d.(VariantDay); ok {
+		all = append(all, vd.Variants(part)...)
+	}
+	return all
+}
+
+// findVariant returns the solver for a part with the given name. A part that doesn't have one by
+// that name falls back to the default, so --solver can name a variant that only one part has.
+func findVariant(d Day, part string, name string) Variant {
+	all := variants(d, part)
+	for _, v := range all {
+		if v.Name == name {
+			return v
+		}
+	}
+	if name != "" {
+		Log.Info("part has no such solver, using the default", "part", part, "solver", name)
+	}
+	return all[0]
+}
+
+// checkSolver makes sure at least one of the parts selected by command has a solver named name
+func checkSolver(d Day, command string, name string) error {
+	if name == "" {
+		return nil
+	}
+
+	names := []string{}
+	for _, part := range []string{"part1", "part2"} {
+		if command != part && command != "all" {
+			continue
+		}
+		for _, v := range variants(d, part) {
+			if v.Name == name {
+				return nil
+			}
+			if !slices.Contains(names, v.Name) {
+				names = append(names, v.Name)
+			}
+		}
+	}
+	return fmt.Errorf("unknown solver %q, the choices are: %s", name, strings.Join(names, ", "))
+}
+
+// crosscheck runs every solver for the parts selected by command against the input, printing each
+// answer, and checks that they agree. Each solver after the first runs on a freshly initialized
+// day (or a clone), so one can't disturb another. Returns the number of parts where the solvers
+// disagreed or failed, and the exit status of the worst failure.
+func crosscheck(ctx context.Context, d Day, number int, in Input, command string, timeout time.Duration) (int, int, error) {
+	init := runPhase(number, "init", in, func() (string, error) {
+		return "", d.Init(in.Path)
+	})
+	if init.Failed() {
+		fmt.Fprint(os.Stderr, init.Stack)
+		return 0, init.ExitStatus, fmt.Errorf("init failed: %s", init.Error)
+	}
+
+	failures, status := 0, 0
+	first := true
+	for _, part := range []string{"part1", "part2"} {
+		if command != part && command != "all" {
+			continue
+		}
+
+		label := fmt.Sprintf("day %d %s %s", number, in.Name, part)
+		answers := map[string][]string{}
+		failed := false
+		for _, v := range variants(d, part) {
+			pd, err := partDay(d, number, in, true, first)
+			first = false
+			if err != nil {
+				writeStack(os.Stderr, err)
+				return failures, exitStatus("init", err), fmt.Errorf("init failed: %w", err)
+			}
+			// the variants were bound to d, so look this one up again on the day it runs on
+			v = findVariant(pd, part, v.Name)
+
+			r := runPhase(number, part, in, func() (string, error) {
+				return runWithContext(ctx, v.Solve, timeout)
+			})
+			switch {
+			case r.Failed():
+				fmt.Printf("     %s %s: ERROR: %s\n", label, v.Name, r.Error)
+				fmt.Fprint(os.Stderr, r.Stack)
+				failed = true
+				status = max(status, r.ExitStatus)
+			case r.Skipped:
+				fmt.Printf("     %s %s: no such part\n", label, v.Name)
+			default:
+				fmt.Printf("     %s %s: %s (%v)\n", label, v.Name, r.Answer, r.Duration().Round(time.Microsecond))
+				answers[r.Answer] = append(answers[r.Answer], v.Name)
+			}
+		}
+
+		switch {
+		case failed:
+			fmt.Printf("FAIL %s: a solver failed\n", label)
+			failures++
+		case len(answers) > 1:
+			groups := []string{}
+			for answer, names := range answers {
+				groups = append(groups, fmt.Sprintf("%s from %s", answer, strings.Join(names, ", ")))
+			}
+			slices.Sort(groups)
+			fmt.Printf("FAIL %s: solvers disagree: %s\n", label, strings.Join(groups, "; "))
+			failures++
+			status = max(status, ExitSolverError)
+		default:
+			fmt.Printf("ok   %s\n", label)
+		}
+	}
+	return failures, status, nil
+}
+
+// crosscheckCommand runs crosscheck for the day in dir, with the part and input given at position
+// i of the command's arguments
+func crosscheckCommand(c *cli.Context, d Day, number int, dir string, i int) error {
+	command := "all"
+	if c.Args().Len() > i {
+		command = c.Args().Get(i)
+	}
+	if command != "part1" && command != "part2" && command != "all" {
+		return fmt.Errorf("unknown part %q", command)
+	}
+
+	ctx, stop := interruptContext(c.Context)
+	defer stop()
+
+	failures, status, err := crosscheck(ctx, d, number, NamedInput(dir, inputArg(c, i+1)), command, c.Duration("timeout"))
+	if err != nil {
+		return cli.Exit(err.Error(), status)
+	}
+	if failures > 0 {
+		return cli.Exit(fmt.Sprintf("the solvers disagreed or failed on %d parts", failures), status)
+	}
+	return nil
+}
+
+// crosscheckFlags are shared by the single and multi-day crosscheck commands
+var crosscheckFlags = []cli.Flag{
+	&cli.DurationFlag{
+		Name:  "timeout",
+		Usage: "stop each solver after this long, e.g. 30s",
+	},
+}
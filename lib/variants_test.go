@@ -0,0 +1,69 @@
+package lib
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// variantDay has a second solver for part 2, which gets a different answer unless agree is set
+type variantDay struct {
+	stubDay
+	agree bool
+}
+
+func (d *variantDay) Variants(part string) []Variant {
+	if part != "part2" {
+		return nil
+	}
+	return []Variant{{Name: "fast", Solve: func(ctx context.Context) (string, error) {
+		if d.agree {
+			return "World", nil
+		}
+		return "Planet", nil
+	}}}
+}
+
+func TestFindVariant(t *testing.T) {
+	d := &variantDay{}
+
+	assert.NoError(t, checkSolver(d, "all", ""))
+	assert.NoError(t, checkSolver(d, "all", "fast"))
+	assert.EqualError(t, checkSolver(d, "part1", "fast"), `unknown solver "fast", the choices are: default`)
+
+	answer, err := findVariant(d, "part2", "fast").Solve(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "Planet", answer)
+
+	v := findVariant(d, "part1", "fast")
+	assert.Equal(t, DefaultVariant, v.Name)
+	answer, err = v.Solve(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "Hello", answer)
+}
+
+func TestRunPartsSolver(t *testing.T) {
+	results := runParts(context.Background(), &variantDay{}, 99, Input{Name: "input"}, "all", runOptions{solver: "fast"}, nopReporter{})
+	require.Len(t, results, 3)
+	assert.Equal(t, Result{Day: 99, Part: "part1", Input: "input", Answer: "Hello", Solver: DefaultVariant, DurationNs: results[1].DurationNs}, results[1])
+	assert.Equal(t, "Planet", results[2].Answer)
+	assert.Equal(t, "fast", results[2].Solver)
+}
+
+func TestCrosscheck(t *testing.T) {
+	failures, status, err := crosscheck(context.Background(), &variantDay{}, 99, Input{Name: "input"}, "all", 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, failures)
+	assert.Equal(t, ExitSolverError, status)
+
+	failures, status, err = crosscheck(context.Background(), &variantDay{agree: true}, 99, Input{Name: "input"}, "part2", 0)
+	require.NoError(t, err)
+	assert.Equal(t, 0, failures)
+	assert.Equal(t, 0, status)
+
+	_, status, err = crosscheck(context.Background(), &panicDay{}, 99, Input{Name: "bad.txt"}, "all", 0)
+	assert.ErrorContains(t, err, "init failed: panic")
+	assert.Equal(t, ExitInputError, status)
+}
@@ -0,0 +1,201 @@
+package lib
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	// watchInterval is how often the watched files are checked for changes
+	watchInterval = 250 * time.Millisecond
+	// watchDebounce is how long the files have to stay unchanged before rerunning, so a burst of
+	// saves only causes one rebuild
+	watchDebounce = 300 * time.Millisecond
+)
+
+// watchSnapshot records the modification time of every watched file
+type watchSnapshot map[string]time.Time
+
+// takeSnapshot finds the day's .go, .txt and encrypted input files under dir
+func takeSnapshot(dir string) (watchSnapshot, error) {
+	snapshot := watchSnapshot{}
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || (filepath.Ext(path) != ".go" && filepath.Ext(path) != ".txt" && filepath.Ext(path) != EncryptedSuffix) {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		snapshot[path] = info.ModTime()
+		return nil
+	})
+	return snapshot, err
+}
+
+// watcher rebuilds and reruns a day whenever its files change
+type watcher struct {
+	// dir is the day's directory, which is built and run from
+	dir     string
+	command string
+	input   string
+	timeout time.Duration
+	out     io.Writer
+
+	binary   string
+	previous map[string]string
+}
+
+// rerun builds the day and runs it, printing the answers with any changes from the last run.
+// Compile errors are printed in place of the answers.
+func (w *watcher) rerun(ctx context.Context) {
+	fmt.Fprintf(w.out, "\n== %s\n", time.Now().Format(time.TimeOnly))
+
+	build := exec.CommandContext(ctx, "go", "build", "-o", w.binary, ".")
+	build.Dir = w.dir
+	output, err := build.CombinedOutput()
+	if err != nil {
+		fmt.Fprintf(w.out, "build failed:\n%s", output)
+		return
+	}
+
+	// timings of half-edited code don't belong in the history
+	args := []string{w.command, "--format", "ndjson", "--no-history"}
+	if w.timeout > 0 {
+		args = append(args, "--timeout", w.timeout.String())
+	}
+	args = append(args, w.input)
+
+	var stdout bytes.Buffer
+	run := exec.CommandContext(ctx, w.binary, args...)
+	run.Dir = w.dir
+	run.Stdout = &stdout
+	run.Stderr = os.Stderr
+	err = run.Run()
+	if err != nil && stdout.Len() == 0 {
+		fmt.Fprintf(w.out, "run failed: %v\n", err)
+		return
+	}
+
+	answers := map[string]string{}
+	scanner := bufio.NewScanner(&stdout)
+	for scanner.Scan() {
+		var r Result
+		if json.Unmarshal(scanner.Bytes(), &r) != nil {
+			continue
+		}
+
+		answer := r.Answer
+		if r.Failed() {
+			answer = "ERROR: " + r.Error
+		} else if r.Skipped {
+			answer = "-"
+		} else if r.Part == "init" {
+			continue
+		}
+		answers[r.Part] = answer
+
+		previous, seen := w.previous[r.Part]
+		switch {
+		case !seen:
+			fmt.Fprintf(w.out, "%s: %s (%v)\n", r.Part, answer, r.Duration().Round(time.Microsecond))
+		case previous == answer:
+			fmt.Fprintf(w.out, "%s: %s (unchanged, %v)\n", r.Part, answer, r.Duration().Round(time.Microsecond))
+		default:
+			fmt.Fprintf(w.out, "%s: %s -> %s (%v)\n", r.Part, previous, answer, r.Duration().Round(time.Microsecond))
+		}
+	}
+	w.previous = answers
+}
+
+// watch reruns the day once up front and then after every change to its files, until ctx is done
+func (w *watcher) watch(ctx context.Context) error {
+	tmp, err := os.MkdirTemp("", "aoc-watch")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+	w.binary = filepath.Join(tmp, "day")
+
+	last, err := takeSnapshot(w.dir)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w.out, "watching %d files in %s\n", len(last), w.dir)
+	w.rerun(ctx)
+
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+
+	var changedAt time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		snapshot, err := takeSnapshot(w.dir)
+		if err != nil {
+			return err
+		}
+		if !maps.Equal(snapshot, last) {
+			last = snapshot
+			changedAt = time.Now()
+			continue
+		}
+
+		if !changedAt.IsZero() && time.Since(changedAt) >= watchDebounce {
+			changedAt = time.Time{}
+			w.rerun(ctx)
+		}
+	}
+}
+
+// watchCommand runs the watch command for the day in dir, with the part and input given at
+// position i of the command's arguments
+func watchCommand(c *cli.Context, dir string, i int) error {
+	command := "all"
+	if c.Args().Len() > i {
+		command = c.Args().Get(i)
+	}
+	if command != "part1" && command != "part2" && command != "all" {
+		return fmt.Errorf("unknown part %q", command)
+	}
+
+	ctx, stop := interruptContext(c.Context)
+	defer stop()
+
+	w := &watcher{
+		dir:     dir,
+		command: command,
+		input:   inputArg(c, i+1),
+		timeout: c.Duration("timeout"),
+		out:     os.Stdout,
+	}
+	return w.watch(ctx)
+}
+
+// watchFlags are shared by the single and multi-day watch commands
+var watchFlags = []cli.Flag{
+	&cli.DurationFlag{
+		Name:  "timeout",
+		Usage: "stop each part after this long, e.g. 30s",
+	},
+}
@@ -0,0 +1,36 @@
+package lib
+
+import (
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTakeSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "main.go"), "package main")
+	writeFile(t, filepath.Join(dir, "solution", "solution.go"), "package solution")
+	writeFile(t, filepath.Join(dir, "sample.txt"), "1,2")
+	writeFile(t, filepath.Join(dir, "answers.json"), "{}")
+
+	before, err := takeSnapshot(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "main.go"),
+		filepath.Join(dir, "sample.txt"),
+		filepath.Join(dir, "solution", "solution.go"),
+	}, slices.Sorted(maps.Keys(before)))
+
+	later := time.Now().Add(time.Second)
+	require.NoError(t, os.Chtimes(filepath.Join(dir, "sample.txt"), later, later))
+
+	after, err := takeSnapshot(dir)
+	require.NoError(t, err)
+	assert.False(t, maps.Equal(before, after))
+}